@@ -0,0 +1,148 @@
+package gridscale
+
+import (
+	"log"
+	"sync"
+
+	"github.com/gridscale/terraform-provider-gridscale/internal/gridscale"
+)
+
+// batchRefresh serves the Reads of servers, storages and networks from one
+// list call per object type. Objects that are missing from the list, or that
+// the provider changed since it was fetched, are read with a targeted GET.
+type batchRefresh struct {
+	servers  objectBatch
+	storages objectBatch
+	networks objectBatch
+
+	mu    sync.Mutex
+	stale map[string]bool
+}
+
+// objectBatch is the collection of one object type, fetched on first use.
+type objectBatch struct {
+	once    sync.Once
+	objects map[string]interface{}
+	err     error
+}
+
+func newBatchRefresh() *batchRefresh {
+	return &batchRefresh{stale: map[string]bool{}}
+}
+
+// lookup returns the object with the given id from the batch, fetching the
+// batch with list on first use. It returns false if the caller has to read
+// the object itself.
+func (b *batchRefresh) lookup(batch *objectBatch, kind, id string, list func() (map[string]interface{}, error)) (interface{}, bool) {
+	b.mu.Lock()
+	stale := b.stale[id]
+	b.mu.Unlock()
+	if stale {
+		return nil, false
+	}
+
+	batch.once.Do(func() {
+		batch.objects, batch.err = list()
+		if batch.err != nil {
+			log.Printf("[WARN] Listing %s for the batched refresh failed, reading them one by one: %s", kind, batch.err)
+		} else {
+			log.Printf("[DEBUG] Fetched %d %s for the batched refresh", len(batch.objects), kind)
+		}
+	})
+	if batch.err != nil {
+		return nil, false
+	}
+	object, ok := batch.objects[id]
+	return object, ok
+}
+
+// markStale makes the next Reads of the object use a targeted GET, because
+// the batch no longer reflects it.
+func (b *batchRefresh) markStale(id string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stale[id] = true
+}
+
+// batchedServer is a server from the batched list. Its relations are
+// decoded the way getServerRelations does, so Read needs no further call.
+type batchedServer struct {
+	gridscale.Server
+	Relations serverRelations `json:"relations"`
+}
+
+// readServer returns a server, from the batch if batched refresh is on. The
+// relations are only returned for a server from the batch, otherwise the
+// caller reads them with getServerRelations.
+func (c *Config) readServer(id string) (*gridscale.Server, *serverRelations, error) {
+	if c.batch != nil {
+		object, ok := c.batch.lookup(&c.batch.servers, "servers", id, func() (map[string]interface{}, error) {
+			wrpr := struct {
+				Servers map[string]*batchedServer `json:"servers"`
+			}{}
+			if err := c.apiCall("GET", "/objects/servers", nil, &wrpr); err != nil {
+				return nil, err
+			}
+			objects := make(map[string]interface{}, len(wrpr.Servers))
+			for serverID, server := range wrpr.Servers {
+				objects[serverID] = server
+			}
+			return objects, nil
+		})
+		if ok {
+			server := object.(*batchedServer)
+			return &server.Server, &server.Relations, nil
+		}
+	}
+	server, err := c.GetServer(id)
+	return server, nil, err
+}
+
+// readStorage returns a storage, from the batch if batched refresh is on.
+func (c *Config) readStorage(id string) (*storageSummary, error) {
+	if c.batch == nil {
+		return c.getStorage(id)
+	}
+	object, ok := c.batch.lookup(&c.batch.storages, "storages", id, func() (map[string]interface{}, error) {
+		storages, err := c.getStorages()
+		if err != nil {
+			return nil, err
+		}
+		objects := make(map[string]interface{}, len(storages))
+		for i := range storages {
+			objects[storages[i].ID] = &storages[i]
+		}
+		return objects, nil
+	})
+	if ok {
+		return object.(*storageSummary), nil
+	}
+	return c.getStorage(id)
+}
+
+// readNetwork returns a network, from the batch if batched refresh is on.
+func (c *Config) readNetwork(id string) (*networkSummary, error) {
+	if c.batch == nil {
+		return c.getNetwork(id)
+	}
+	object, ok := c.batch.lookup(&c.batch.networks, "networks", id, func() (map[string]interface{}, error) {
+		wrpr := struct {
+			Networks map[string]*networkSummary `json:"networks"`
+		}{}
+		if err := c.apiCall("GET", "/objects/networks", nil, &wrpr); err != nil {
+			return nil, err
+		}
+		objects := make(map[string]interface{}, len(wrpr.Networks))
+		for networkID, network := range wrpr.Networks {
+			objects[networkID] = network
+		}
+		return objects, nil
+	})
+	if ok {
+		return object.(*networkSummary), nil
+	}
+	return c.getNetwork(id)
+}
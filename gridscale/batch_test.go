@@ -0,0 +1,126 @@
+package gridscale
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gridscale/terraform-provider-gridscale/internal/gridscale"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestBatchRefresh_lookup(t *testing.T) {
+	b := newBatchRefresh()
+	calls := 0
+	list := func() (map[string]interface{}, error) {
+		calls++
+		return map[string]interface{}{"a": "object a", "b": "object b"}, nil
+	}
+
+	for _, id := range []string{"a", "b", "a"} {
+		object, ok := b.lookup(&b.servers, "servers", id, list)
+		if !ok || object.(string) != "object "+id {
+			t.Fatalf("lookup(%q) = %v, %t", id, object, ok)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected one list call, got %d", calls)
+	}
+
+	if _, ok := b.lookup(&b.servers, "servers", "missing", list); ok {
+		t.Fatal("expected a missing object to fall back to a targeted read")
+	}
+
+	b.markStale("a")
+	if _, ok := b.lookup(&b.servers, "servers", "a", list); ok {
+		t.Fatal("expected a stale object to fall back to a targeted read")
+	}
+	if _, ok := b.lookup(&b.servers, "servers", "b", list); !ok {
+		t.Fatal("expected other objects to still be served from the batch")
+	}
+
+	// Each object type has its own batch.
+	b.lookup(&b.storages, "storages", "s", list)
+	if calls != 2 {
+		t.Fatalf("expected a list call for storages, got %d calls", calls)
+	}
+}
+
+func TestBatchRefresh_listError(t *testing.T) {
+	b := newBatchRefresh()
+	calls := 0
+	list := func() (map[string]interface{}, error) {
+		calls++
+		return nil, errors.New("list failed")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, ok := b.lookup(&b.networks, "networks", "a", list); ok {
+			t.Fatal("expected a failed list to fall back to targeted reads")
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected the failed list call not to be repeated, got %d calls", calls)
+	}
+}
+
+func TestBatchRefresh_serverRequests(t *testing.T) {
+	const n = 3
+	servers := []string{}
+	for i := 0; i < n; i++ {
+		servers = append(servers, fmt.Sprintf(`"server-%d": {
+			"object_uuid": "server-%d", "name": "web-%d", "status": "active",
+			"relations": {"storages": [{"object_uuid": "storage-%d", "bootdevice": true}]}
+		}`, i, i, i, i))
+	}
+
+	var mu sync.Mutex
+	requests := map[string]int{}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/objects/servers":
+			w.Write([]byte(`{"servers": {` + strings.Join(servers, ",") + `}}`))
+		case "/prices":
+			w.Write([]byte(`{"prices": []}`))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer api.Close()
+
+	client, _ := gridscale.NewClient("user-uuid", "token", api.URL)
+	api_client := &Config{Client: client, Endpoint: api.URL, batch: newBatchRefresh()}
+
+	for i := 0; i < n; i++ {
+		d := schema.TestResourceDataRaw(t, resourceGridScaleServer().Schema, map[string]interface{}{
+			"name":       fmt.Sprintf("web-%d", i),
+			"cores":      1,
+			"memory":     1,
+			"storage_id": fmt.Sprintf("storage-%d", i),
+		})
+		d.SetId(fmt.Sprintf("server-%d", i))
+		if err := resourceGridScaleServerRead(d, api_client); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if d.Get("storage_id").(string) != fmt.Sprintf("storage-%d", i) || !d.Get("bootdevice").(bool) {
+			t.Fatalf("expected the relations of server-%d to be read from the batch", i)
+		}
+	}
+
+	serverRequests := 0
+	for path, count := range requests {
+		if strings.HasPrefix(path, "/objects/servers") {
+			serverRequests += count
+		}
+	}
+	if requests["/objects/servers"] != 1 || serverRequests != 1 {
+		t.Fatalf("expected one list call for %d servers, got %v", n, requests)
+	}
+}
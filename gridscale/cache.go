@@ -0,0 +1,168 @@
+package gridscale
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gridscale/terraform-provider-gridscale/internal/gridscale"
+)
+
+// Keys of the cached list endpoints.
+const (
+	cacheLocations      = "locations"
+	cacheTemplates      = "templates"
+	cachePrices         = "prices"
+	cachePublicNetworks = "public_networks"
+)
+
+// listCache keeps the responses of read-only list endpoints for the
+// lifetime of a provider configuration. Concurrent lookups of the same key
+// share one API call. Errors are not cached.
+type listCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+type cacheEntry struct {
+	ready   chan struct{}
+	value   interface{}
+	err     error
+	expires time.Time
+}
+
+func newListCache(ttl time.Duration) *listCache {
+	return &listCache{ttl: ttl, now: time.Now, entries: map[string]*cacheEntry{}}
+}
+
+// get returns the cached value of key, calling fetch if there is none or it
+// has expired. A nil cache or a TTL of zero disables caching.
+func (c *listCache) get(key string, fetch func() (interface{}, error)) (interface{}, error) {
+	if c == nil || c.ttl <= 0 {
+		return fetch()
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok {
+		select {
+		case <-entry.ready:
+			if entry.err != nil || !c.now().Before(entry.expires) {
+				ok = false
+			}
+		default:
+			// Another lookup is in flight, wait for it below.
+		}
+	}
+	if !ok {
+		entry = &cacheEntry{ready: make(chan struct{})}
+		c.entries[key] = entry
+		c.mu.Unlock()
+
+		entry.value, entry.err = fetch()
+		entry.expires = c.now().Add(c.ttl)
+		close(entry.ready)
+		return entry.value, entry.err
+	}
+	c.mu.Unlock()
+
+	<-entry.ready
+	return entry.value, entry.err
+}
+
+// cacheInvalidations lists the cache entries that a mutating call below an
+// API path makes stale.
+var cacheInvalidations = map[string][]string{
+	"/objects/templates": {cacheTemplates},
+}
+
+// invalidatePath drops the cache entries affected by a mutating call to path.
+func (c *listCache) invalidatePath(path string) {
+	for prefix, keys := range cacheInvalidations {
+		if strings.HasPrefix(path, prefix) {
+			c.invalidate(keys...)
+		}
+	}
+}
+
+// invalidate drops the given keys, so the next lookup calls the API again.
+func (c *listCache) invalidate(keys ...string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+}
+
+func (c *Config) getLocations() ([]gridscale.Location, error) {
+	v, err := c.cache.get(cacheLocations, func() (interface{}, error) {
+		return c.GetLocations()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]gridscale.Location), nil
+}
+
+func (c *Config) getTemplates() ([]gridscale.Template, error) {
+	v, err := c.cache.get(cacheTemplates, func() (interface{}, error) {
+		return c.GetTemplates()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]gridscale.Template), nil
+}
+
+// getTemplateByName looks a template up in the cached template list rather
+// than fetching the whole list on every call like the client library does.
+func (c *Config) getTemplateByName(name string) (*gridscale.Template, error) {
+	templates, err := c.getTemplates()
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range templates {
+		if template.Name == name {
+			return &template, nil
+		}
+	}
+	return nil, fmt.Errorf("Template %q not found", name)
+}
+
+func (c *Config) getPrices() ([]gridscale.Price, error) {
+	v, err := c.cache.get(cachePrices, func() (interface{}, error) {
+		return c.GetPrices()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]gridscale.Price), nil
+}
+
+// getPublicNetworks returns the public networks of all locations.
+func (c *Config) getPublicNetworks() ([]gridscale.Network, error) {
+	v, err := c.cache.get(cachePublicNetworks, func() (interface{}, error) {
+		networks, err := c.GetNetworks()
+		if err != nil {
+			return nil, err
+		}
+		public := []gridscale.Network{}
+		for _, network := range networks {
+			if network.PublicNet {
+				public = append(public, network)
+			}
+		}
+		return public, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]gridscale.Network), nil
+}
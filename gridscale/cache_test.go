@@ -0,0 +1,127 @@
+package gridscale
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestListCache_ttl(t *testing.T) {
+	now := time.Now()
+	cache := newListCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	calls := 0
+	fetch := func() (interface{}, error) {
+		calls++
+		return calls, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		v, err := cache.get("key", fetch)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if v.(int) != 1 {
+			t.Fatalf("expected the cached value, got %d", v.(int))
+		}
+	}
+
+	now = now.Add(2 * time.Minute)
+	if v, _ := cache.get("key", fetch); v.(int) != 2 {
+		t.Fatalf("expected an expired entry to be fetched again, got %d", v.(int))
+	}
+
+	cache.invalidate("key")
+	if v, _ := cache.get("key", fetch); v.(int) != 3 {
+		t.Fatalf("expected an invalidated entry to be fetched again, got %d", v.(int))
+	}
+}
+
+func TestListCache_errorsNotCached(t *testing.T) {
+	cache := newListCache(time.Minute)
+	calls := 0
+	fetch := func() (interface{}, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("temporary failure")
+		}
+		return "ok", nil
+	}
+
+	if _, err := cache.get("key", fetch); err == nil {
+		t.Fatal("expected the error of the first call")
+	}
+	if v, err := cache.get("key", fetch); err != nil || v.(string) != "ok" {
+		t.Fatalf("expected the second call to be made, got %v, %v", v, err)
+	}
+}
+
+func TestListCache_concurrent(t *testing.T) {
+	cache := newListCache(time.Minute)
+	var mu sync.Mutex
+	calls := 0
+	release := make(chan struct{})
+	fetch := func() (interface{}, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		<-release
+		return "value", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := cache.get("key", fetch); err != nil || v.(string) != "value" {
+				t.Errorf("unexpected result %v, %v", v, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("expected concurrent lookups to share one call, got %d", calls)
+	}
+}
+
+func TestListCache_invalidatePath(t *testing.T) {
+	cache := newListCache(time.Minute)
+	calls := 0
+	fetch := func() (interface{}, error) {
+		calls++
+		return calls, nil
+	}
+
+	cache.get(cacheTemplates, fetch)
+	cache.invalidatePath("/objects/storages/uuid")
+	cache.get(cacheTemplates, fetch)
+	if calls != 1 {
+		t.Fatalf("expected unrelated paths to keep the cache, got %d calls", calls)
+	}
+	cache.invalidatePath("/objects/templates/uuid")
+	cache.get(cacheTemplates, fetch)
+	if calls != 2 {
+		t.Fatalf("expected template changes to invalidate the cache, got %d calls", calls)
+	}
+}
+
+func TestListCache_disabled(t *testing.T) {
+	var cache *listCache
+	calls := 0
+	fetch := func() (interface{}, error) {
+		calls++
+		return calls, nil
+	}
+	cache.get("key", fetch)
+	cache.get("key", fetch)
+	cache.invalidatePath("/objects/templates")
+	if calls != 2 {
+		t.Fatalf("expected a nil cache to always fetch, got %d calls", calls)
+	}
+}
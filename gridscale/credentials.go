@@ -0,0 +1,217 @@
+package gridscale
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// defaultProfile is used when no profile is selected.
+const defaultProfile = "default"
+
+// credentials are the settings needed to talk to the API, gathered from the
+// provider arguments, a profile, a token file or a credential process.
+type credentials struct {
+	APIURL            string `json:"api_url"`
+	UserUUID          string `json:"user_uuid"`
+	APIToken          string `json:"api_token"`
+	APITokenFile      string `json:"-"`
+	CredentialProcess string `json:"-"`
+}
+
+// merge fills the fields that are still empty from other.
+func (c *credentials) merge(other credentials) {
+	if c.APIURL == "" {
+		c.APIURL = other.APIURL
+	}
+	if c.UserUUID == "" {
+		c.UserUUID = other.UserUUID
+	}
+	if c.APIToken == "" {
+		c.APIToken = other.APIToken
+	}
+	if c.APITokenFile == "" {
+		c.APITokenFile = other.APITokenFile
+	}
+	if c.CredentialProcess == "" {
+		c.CredentialProcess = other.CredentialProcess
+	}
+}
+
+func (c *credentials) complete() bool {
+	return c.APIURL != "" && c.UserUUID != "" && c.APIToken != ""
+}
+
+// defaultConfigFile returns ~/.gridscale/config.
+func defaultConfigFile() string {
+	home, err := homedir.Dir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".gridscale", "config")
+}
+
+// parseProfiles reads an INI style config file with one section per
+// profile:
+//
+//	[staging]
+//	api_url = https://api.gridscale.io
+//	user_uuid = ...
+//	api_token_file = ~/.gridscale/staging-token
+func parseProfiles(r io.Reader) (map[string]credentials, error) {
+	profiles := map[string]credentials{}
+	var section string
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			profiles[section] = credentials{}
+			continue
+		}
+
+		i := strings.Index(line, "=")
+		if i < 0 || section == "" {
+			return nil, fmt.Errorf("line %d: expected a [profile] header or a key = value pair", lineNo)
+		}
+		key := strings.TrimSpace(line[:i])
+		value := strings.TrimSpace(line[i+1:])
+
+		profile := profiles[section]
+		switch key {
+		case "api_url":
+			profile.APIURL = value
+		case "user_uuid":
+			profile.UserUUID = value
+		case "api_token":
+			profile.APIToken = value
+		case "api_token_file":
+			profile.APITokenFile = value
+		case "credential_process":
+			profile.CredentialProcess = value
+		default:
+			return nil, fmt.Errorf("line %d: unknown key %q", lineNo, key)
+		}
+		profiles[section] = profile
+	}
+	return profiles, scanner.Err()
+}
+
+// readProfile returns the named profile from the config file at path. A
+// missing file or profile is only an error if the profile was selected
+// explicitly.
+func readProfile(path, name string, required bool) (credentials, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) && !required {
+		return credentials{}, nil
+	}
+	if err != nil {
+		return credentials{}, err
+	}
+	defer f.Close()
+
+	profiles, err := parseProfiles(f)
+	if err != nil {
+		return credentials{}, fmt.Errorf("Error reading %s: %s", path, err)
+	}
+	profile, ok := profiles[name]
+	if !ok && required {
+		return credentials{}, fmt.Errorf("Profile %q not found in %s", name, path)
+	}
+	return profile, nil
+}
+
+// expandHome replaces a leading ~ with the home directory.
+func expandHome(path string) string {
+	expanded, err := homedir.Expand(path)
+	if err != nil {
+		return path
+	}
+	return expanded
+}
+
+// readTokenFile sets the token from APITokenFile unless it is already set.
+func (c *credentials) readTokenFile() error {
+	if c.APIToken != "" || c.APITokenFile == "" {
+		return nil
+	}
+	b, err := ioutil.ReadFile(expandHome(c.APITokenFile))
+	if err != nil {
+		return fmt.Errorf("Error reading api_token_file: %s", err)
+	}
+	c.APIToken = strings.TrimSpace(string(b))
+	return nil
+}
+
+// runCredentialProcess runs command through the shell. The command prints a
+// JSON object with any of api_url, user_uuid and api_token on stdout.
+func runCredentialProcess(command string) (credentials, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return credentials{}, fmt.Errorf("credential_process failed: %s: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	creds := credentials{}
+	if err := json.Unmarshal(stdout.Bytes(), &creds); err != nil {
+		return credentials{}, fmt.Errorf("credential_process did not print valid JSON: %s", err)
+	}
+	return creds, nil
+}
+
+// resolveCredentials completes the explicitly configured credentials. The
+// provider arguments come first, then the selected profile and finally the
+// credential process. A token file counts as part of the place it is
+// configured in.
+func resolveCredentials(explicit credentials, configFile, profile string) (credentials, error) {
+	creds := explicit
+	if err := creds.readTokenFile(); err != nil {
+		return creds, err
+	}
+
+	required := profile != ""
+	if profile == "" {
+		profile = defaultProfile
+	}
+	if configFile == "" {
+		configFile = defaultConfigFile()
+	}
+	if configFile != "" {
+		fromProfile, err := readProfile(expandHome(configFile), profile, required)
+		if err != nil {
+			return creds, err
+		}
+		if err := fromProfile.readTokenFile(); err != nil {
+			return creds, err
+		}
+		creds.merge(fromProfile)
+	}
+
+	if !creds.complete() && creds.CredentialProcess != "" {
+		fromProcess, err := runCredentialProcess(creds.CredentialProcess)
+		if err != nil {
+			return creds, err
+		}
+		creds.merge(fromProcess)
+	}
+
+	if !creds.complete() {
+		return creds, fmt.Errorf("api_url, user_uuid and api_token must be set, either as provider arguments, " +
+			"environment variables, in a ~/.gridscale/config profile, through api_token_file or credential_process")
+	}
+	return creds, nil
+}
@@ -0,0 +1,103 @@
+package gridscale
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testConfigFile = `
+# gridscale profiles
+[default]
+api_url = https://api.gridscale.io
+user_uuid = default-user
+api_token = default-token
+
+[staging]
+api_url = https://staging.example.com
+user_uuid = staging-user
+api_token_file = %s
+
+[vault]
+api_url = https://api.gridscale.io
+credential_process = echo '{"user_uuid": "vault-user", "api_token": "vault-token"}'
+`
+
+func writeTestConfig(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "gridscale-credentials")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	tokenFile := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(tokenFile, []byte("staging-token\n"), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	configFile := filepath.Join(dir, "config")
+	content := strings.Replace(testConfigFile, "%s", tokenFile, 1)
+	if err := ioutil.WriteFile(configFile, []byte(content), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return configFile, func() { os.RemoveAll(dir) }
+}
+
+func TestParseProfiles_invalid(t *testing.T) {
+	for _, content := range []string{
+		"api_url = https://api.gridscale.io",
+		"[default]\nunknown = value",
+		"[default]\nno separator",
+	} {
+		if _, err := parseProfiles(strings.NewReader(content)); err == nil {
+			t.Errorf("expected an error for %q", content)
+		}
+	}
+}
+
+func TestResolveCredentials(t *testing.T) {
+	configFile, cleanup := writeTestConfig(t)
+	defer cleanup()
+
+	cases := []struct {
+		name     string
+		explicit credentials
+		profile  string
+		expected credentials
+	}{
+		{
+			name:     "default profile",
+			expected: credentials{APIURL: "https://api.gridscale.io", UserUUID: "default-user", APIToken: "default-token"},
+		},
+		{
+			name:     "arguments win over the profile",
+			explicit: credentials{APIToken: "explicit-token"},
+			expected: credentials{APIURL: "https://api.gridscale.io", UserUUID: "default-user", APIToken: "explicit-token"},
+		},
+		{
+			name:     "token file",
+			profile:  "staging",
+			expected: credentials{APIURL: "https://staging.example.com", UserUUID: "staging-user", APIToken: "staging-token"},
+		},
+		{
+			name:     "credential process",
+			profile:  "vault",
+			expected: credentials{APIURL: "https://api.gridscale.io", UserUUID: "vault-user", APIToken: "vault-token"},
+		},
+	}
+	for _, tc := range cases {
+		creds, err := resolveCredentials(tc.explicit, configFile, tc.profile)
+		if err != nil {
+			t.Fatalf("%s: err: %s", tc.name, err)
+		}
+		if creds.APIURL != tc.expected.APIURL || creds.UserUUID != tc.expected.UserUUID || creds.APIToken != tc.expected.APIToken {
+			t.Errorf("%s: got %+v, expected %+v", tc.name, creds, tc.expected)
+		}
+	}
+
+	if _, err := resolveCredentials(credentials{}, configFile, "missing"); err == nil {
+		t.Fatal("expected an error for a missing profile")
+	}
+	if _, err := resolveCredentials(credentials{}, filepath.Join(filepath.Dir(configFile), "none"), ""); err == nil {
+		t.Fatal("expected an error for incomplete credentials")
+	}
+}
@@ -0,0 +1,73 @@
+package gridscale
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceGridScaleIPs() *schema.Resource {
+	s := listFilterSchema(true)
+	// IP addresses have no name, so name_regex is matched against the address.
+	s["name_regex"].Description = "Only return IP addresses matching this regular expression."
+	s["ips"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"id":            {Type: schema.TypeString, Computed: true},
+				"ip":            {Type: schema.TypeString, Computed: true},
+				"prefix":        {Type: schema.TypeString, Computed: true},
+				"family":        {Type: schema.TypeInt, Computed: true},
+				"failover":      {Type: schema.TypeBool, Computed: true},
+				"reverse_dns":   {Type: schema.TypeString, Computed: true},
+				"location_uuid": {Type: schema.TypeString, Computed: true},
+				"labels": {
+					Type:     schema.TypeList,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+					Computed: true,
+				},
+			},
+		},
+	}
+
+	return &schema.Resource{
+		Read:   dataSourceGridScaleIPsRead,
+		Schema: s,
+	}
+}
+
+func dataSourceGridScaleIPsRead(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	filter, err := newListFilter(d)
+	if err != nil {
+		return err
+	}
+
+	ips, err := api_client.GetIPs()
+	if err != nil {
+		return err
+	}
+
+	entries := []listEntry{}
+	for _, ip := range ips {
+		address := ip.IP.String()
+		if !filter.match(address, ip.LocationID, ip.Labels) {
+			continue
+		}
+		entries = append(entries, listEntry{
+			id:   ip.ID,
+			name: address,
+			attrs: map[string]interface{}{
+				"id":            ip.ID,
+				"ip":            address,
+				"prefix":        ip.Prefix.String(),
+				"family":        ip.IPVersion,
+				"failover":      ip.Failover,
+				"reverse_dns":   ip.ReverseDNS,
+				"location_uuid": ip.LocationID,
+				"labels":        ip.Labels,
+			},
+		})
+	}
+
+	return setListResult(d, "ips", entries)
+}
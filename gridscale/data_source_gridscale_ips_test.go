@@ -0,0 +1,28 @@
+package gridscale
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceGridScaleIPs_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDataSourceGridScaleIPsConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.gridscale_ips.all", "ids.#"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCheckDataSourceGridScaleIPsConfig_basic = `
+data "gridscale_ips" "all" {
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+}
+`
@@ -0,0 +1,68 @@
+package gridscale
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceGridScaleNetworks() *schema.Resource {
+	s := listFilterSchema(true)
+	s["networks"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"id":            {Type: schema.TypeString, Computed: true},
+				"name":          {Type: schema.TypeString, Computed: true},
+				"status":        {Type: schema.TypeString, Computed: true},
+				"public_net":    {Type: schema.TypeBool, Computed: true},
+				"l2security":    {Type: schema.TypeBool, Computed: true},
+				"location_uuid": {Type: schema.TypeString, Computed: true},
+				"labels": {
+					Type:     schema.TypeList,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+					Computed: true,
+				},
+			},
+		},
+	}
+
+	return &schema.Resource{
+		Read:   dataSourceGridScaleNetworksRead,
+		Schema: s,
+	}
+}
+
+func dataSourceGridScaleNetworksRead(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	filter, err := newListFilter(d)
+	if err != nil {
+		return err
+	}
+
+	networks, err := api_client.GetNetworks()
+	if err != nil {
+		return err
+	}
+
+	entries := []listEntry{}
+	for _, network := range networks {
+		if !filter.match(network.Name, network.LocationID, network.Labels) {
+			continue
+		}
+		entries = append(entries, listEntry{
+			id:   network.ID,
+			name: network.Name,
+			attrs: map[string]interface{}{
+				"id":            network.ID,
+				"name":          network.Name,
+				"status":        network.Status,
+				"public_net":    network.PublicNet,
+				"l2security":    network.L2Security,
+				"location_uuid": network.LocationID,
+				"labels":        network.Labels,
+			},
+		})
+	}
+
+	return setListResult(d, "networks", entries)
+}
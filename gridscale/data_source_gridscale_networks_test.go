@@ -0,0 +1,35 @@
+package gridscale
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceGridScaleNetworks_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDataSourceGridScaleNetworksConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.gridscale_networks.private", "ids.#", "1"),
+					resource.TestCheckResourceAttr("data.gridscale_networks.private", "networks.0.name", "dstest-private"),
+					resource.TestCheckResourceAttr("data.gridscale_networks.private", "networks.0.public_net", "false"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCheckDataSourceGridScaleNetworksConfig_basic = `
+resource "gridscale_network" "private" {
+  name = "dstest-private"
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+}
+
+data "gridscale_networks" "private" {
+  name_regex = "^${gridscale_network.private.name}$"
+}
+`
@@ -0,0 +1,89 @@
+package gridscale
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceGridScalePrices() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceGridScalePricesRead,
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return prices of this type.",
+			},
+			// Hourly prices of the products the resources estimate their
+			// cost from, for use in expressions.
+			"core_hourly_price":    {Type: schema.TypeFloat, Computed: true},
+			"memory_hourly_price":  {Type: schema.TypeFloat, Computed: true},
+			"storage_hourly_price": {Type: schema.TypeFloat, Computed: true},
+			"prices": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type":           {Type: schema.TypeString, Computed: true},
+						"name":           {Type: schema.TypeString, Computed: true},
+						"product_no":     {Type: schema.TypeInt, Computed: true},
+						"currency":       {Type: schema.TypeString, Computed: true},
+						"unit":           {Type: schema.TypeString, Computed: true},
+						"price_per_unit": {Type: schema.TypeFloat, Computed: true},
+						"hourly_price":   {Type: schema.TypeFloat, Computed: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceGridScalePricesRead(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+
+	prices, err := api_client.getPrices()
+	if err != nil {
+		return err
+	}
+
+	for key, types := range map[string][]string{
+		"core_hourly_price":    corePriceTypes,
+		"memory_hourly_price":  memoryPriceTypes,
+		"storage_hourly_price": storagePriceTypes,
+	} {
+		if p, err := findPrice(prices, types); err == nil {
+			d.Set(key, hourlyPrice(p))
+		}
+	}
+
+	priceType := d.Get("type").(string)
+	products := []string{}
+	list := []map[string]interface{}{}
+	for _, p := range prices {
+		if priceType != "" && !strings.EqualFold(p.Type, priceType) {
+			continue
+		}
+		price, _ := p.PricePerUnit.Float64()
+		products = append(products, strconv.Itoa(p.ProductNo))
+		list = append(list, map[string]interface{}{
+			"type":           p.Type,
+			"name":           p.Name,
+			"product_no":     p.ProductNo,
+			"currency":       p.Currency,
+			"unit":           p.Unit,
+			"price_per_unit": price,
+			"hourly_price":   hourlyPrice(p),
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i]["product_no"].(int) < list[j]["product_no"].(int)
+	})
+	sort.Strings(products)
+
+	d.SetId(strconv.Itoa(hashcode.String(priceType + ":" + strings.Join(products, ","))))
+	return d.Set("prices", list)
+}
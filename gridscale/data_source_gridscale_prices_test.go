@@ -0,0 +1,28 @@
+package gridscale
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceGridScalePrices_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDataSourceGridScalePricesConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.gridscale_prices.all", "prices.#"),
+					resource.TestCheckResourceAttrSet("data.gridscale_prices.all", "core_hourly_price"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCheckDataSourceGridScalePricesConfig_basic = `
+data "gridscale_prices" "all" {
+}
+`
@@ -0,0 +1,56 @@
+package gridscale
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceGridScalePublicNetwork() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceGridScalePublicNetworkRead,
+		Schema: map[string]*schema.Schema{
+			"location_uuid": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Location of the public network. Defaults to the provider's default_location, or else the first public network found.",
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"l2security": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceGridScalePublicNetworkRead(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+
+	locationId := d.Get("location_uuid").(string)
+	if locationId == "" {
+		var err error
+		locationId, err = api_client.defaultLocationUUID()
+		if err != nil {
+			return err
+		}
+	}
+
+	network, err := api_client.getPublicNetwork(locationId)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(network.ID)
+	d.Set("location_uuid", network.LocationID)
+	d.Set("name", network.Name)
+	d.Set("status", network.Status)
+	d.Set("l2security", network.L2Security)
+	return nil
+}
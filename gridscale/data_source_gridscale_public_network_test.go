@@ -0,0 +1,29 @@
+package gridscale
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceGridScalePublicNetwork_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDataSourceGridScalePublicNetworkConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.gridscale_public_network.pubnet", "id"),
+					resource.TestCheckResourceAttr("data.gridscale_public_network.pubnet", "location_uuid", "45ed677b-3702-4b36-be2a-a2eab9827950"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCheckDataSourceGridScalePublicNetworkConfig_basic = `
+data "gridscale_public_network" "pubnet" {
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+}
+`
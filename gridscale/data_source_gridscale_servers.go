@@ -0,0 +1,68 @@
+package gridscale
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceGridScaleServers() *schema.Resource {
+	s := listFilterSchema(true)
+	s["servers"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"id":            {Type: schema.TypeString, Computed: true},
+				"name":          {Type: schema.TypeString, Computed: true},
+				"status":        {Type: schema.TypeString, Computed: true},
+				"cores":         {Type: schema.TypeInt, Computed: true},
+				"power":         {Type: schema.TypeBool, Computed: true},
+				"location_uuid": {Type: schema.TypeString, Computed: true},
+				"labels": {
+					Type:     schema.TypeList,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+					Computed: true,
+				},
+			},
+		},
+	}
+
+	return &schema.Resource{
+		Read:   dataSourceGridScaleServersRead,
+		Schema: s,
+	}
+}
+
+func dataSourceGridScaleServersRead(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	filter, err := newListFilter(d)
+	if err != nil {
+		return err
+	}
+
+	servers, err := api_client.GetServers()
+	if err != nil {
+		return err
+	}
+
+	entries := []listEntry{}
+	for _, server := range servers {
+		if !filter.match(server.Name, server.LocationID, server.Labels) {
+			continue
+		}
+		entries = append(entries, listEntry{
+			id:   server.ID,
+			name: server.Name,
+			attrs: map[string]interface{}{
+				"id":            server.ID,
+				"name":          server.Name,
+				"status":        server.Status,
+				"cores":         server.Cores,
+				"power":         server.Power,
+				"location_uuid": server.LocationID,
+				"labels":        server.Labels,
+			},
+		})
+	}
+
+	return setListResult(d, "servers", entries)
+}
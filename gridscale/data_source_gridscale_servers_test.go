@@ -0,0 +1,37 @@
+package gridscale
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceGridScaleServers_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDataSourceGridScaleServersConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.gridscale_servers.web", "ids.#", "1"),
+					resource.TestCheckResourceAttr("data.gridscale_servers.web", "servers.0.name", "dstest-web"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCheckDataSourceGridScaleServersConfig_basic = `
+resource "gridscale_server" "web" {
+  name = "dstest-web"
+  cores = 1
+  memory = 1
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+}
+
+data "gridscale_servers" "web" {
+  name_regex = "^${gridscale_server.web.name}$"
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+}
+`
@@ -0,0 +1,72 @@
+package gridscale
+
+import (
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceGridScaleSnapshots() *schema.Resource {
+	s := listFilterSchema(false)
+	s["storage_id"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+	}
+	s["snapshots"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"id":          {Type: schema.TypeString, Computed: true},
+				"name":        {Type: schema.TypeString, Computed: true},
+				"status":      {Type: schema.TypeString, Computed: true},
+				"capacity":    {Type: schema.TypeInt, Computed: true},
+				"create_time": {Type: schema.TypeString, Computed: true},
+				"labels": {
+					Type:     schema.TypeList,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+					Computed: true,
+				},
+			},
+		},
+	}
+
+	return &schema.Resource{
+		Read:   dataSourceGridScaleSnapshotsRead,
+		Schema: s,
+	}
+}
+
+func dataSourceGridScaleSnapshotsRead(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	filter, err := newListFilter(d)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := api_client.getSnapshots(d.Get("storage_id").(string))
+	if err != nil {
+		return err
+	}
+
+	entries := []listEntry{}
+	for _, snapshot := range snapshots {
+		if !filter.match(snapshot.Name, "", snapshot.Labels) {
+			continue
+		}
+		entries = append(entries, listEntry{
+			id:   snapshot.ID,
+			name: snapshot.Name,
+			attrs: map[string]interface{}{
+				"id":          snapshot.ID,
+				"name":        snapshot.Name,
+				"status":      snapshot.Status,
+				"capacity":    snapshot.Capacity,
+				"create_time": snapshot.CreateTime.Format(time.RFC3339),
+				"labels":      snapshot.Labels,
+			},
+		})
+	}
+
+	return setListResult(d, "snapshots", entries)
+}
@@ -0,0 +1,41 @@
+package gridscale
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceGridScaleSnapshots_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDataSourceGridScaleSnapshotsConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.gridscale_snapshots.backups", "ids.#", "1"),
+					resource.TestCheckResourceAttr("data.gridscale_snapshots.backups", "snapshots.0.name", "nightly-1"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCheckDataSourceGridScaleSnapshotsConfig_basic = `
+resource "gridscale_storage" "snapshotted" {
+  name = "snapshotted"
+  capacity = 1
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+}
+
+resource "gridscale_snapshot" "nightly" {
+  storage_id = "${gridscale_storage.snapshotted.id}"
+  name = "nightly-1"
+}
+
+data "gridscale_snapshots" "backups" {
+  storage_id = "${gridscale_storage.snapshotted.id}"
+  name_regex = "^${gridscale_snapshot.nightly.name}$"
+}
+`
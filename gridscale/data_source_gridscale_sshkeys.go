@@ -0,0 +1,63 @@
+package gridscale
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceGridScaleSSHKeys() *schema.Resource {
+	// SSH keys are not bound to a location.
+	s := listFilterSchema(false)
+	s["sshkeys"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"id":     {Type: schema.TypeString, Computed: true},
+				"name":   {Type: schema.TypeString, Computed: true},
+				"sshkey": {Type: schema.TypeString, Computed: true},
+				"labels": {
+					Type:     schema.TypeList,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+					Computed: true,
+				},
+			},
+		},
+	}
+
+	return &schema.Resource{
+		Read:   dataSourceGridScaleSSHKeysRead,
+		Schema: s,
+	}
+}
+
+func dataSourceGridScaleSSHKeysRead(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	filter, err := newListFilter(d)
+	if err != nil {
+		return err
+	}
+
+	keys, err := api_client.GetSSHKeys()
+	if err != nil {
+		return err
+	}
+
+	entries := []listEntry{}
+	for _, key := range keys {
+		if !filter.match(key.Name, "", key.Labels) {
+			continue
+		}
+		entries = append(entries, listEntry{
+			id:   key.ID,
+			name: key.Name,
+			attrs: map[string]interface{}{
+				"id":     key.ID,
+				"name":   key.Name,
+				"sshkey": key.PublicKey,
+				"labels": key.Labels,
+			},
+		})
+	}
+
+	return setListResult(d, "sshkeys", entries)
+}
@@ -0,0 +1,27 @@
+package gridscale
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceGridScaleSSHKeys_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDataSourceGridScaleSSHKeysConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.gridscale_sshkeys.all", "ids.#"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCheckDataSourceGridScaleSSHKeysConfig_basic = `
+data "gridscale_sshkeys" "all" {
+}
+`
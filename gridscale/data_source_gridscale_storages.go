@@ -0,0 +1,66 @@
+package gridscale
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceGridScaleStorages() *schema.Resource {
+	s := listFilterSchema(true)
+	s["storages"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"id":            {Type: schema.TypeString, Computed: true},
+				"name":          {Type: schema.TypeString, Computed: true},
+				"status":        {Type: schema.TypeString, Computed: true},
+				"capacity":      {Type: schema.TypeInt, Computed: true},
+				"location_uuid": {Type: schema.TypeString, Computed: true},
+				"labels": {
+					Type:     schema.TypeList,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+					Computed: true,
+				},
+			},
+		},
+	}
+
+	return &schema.Resource{
+		Read:   dataSourceGridScaleStoragesRead,
+		Schema: s,
+	}
+}
+
+func dataSourceGridScaleStoragesRead(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	filter, err := newListFilter(d)
+	if err != nil {
+		return err
+	}
+
+	storages, err := api_client.getStorages()
+	if err != nil {
+		return err
+	}
+
+	entries := []listEntry{}
+	for _, storage := range storages {
+		if !filter.match(storage.Name, storage.LocationID, storage.Labels) {
+			continue
+		}
+		entries = append(entries, listEntry{
+			id:   storage.ID,
+			name: storage.Name,
+			attrs: map[string]interface{}{
+				"id":            storage.ID,
+				"name":          storage.Name,
+				"status":        storage.Status,
+				"capacity":      storage.Capacity,
+				"location_uuid": storage.LocationID,
+				"labels":        storage.Labels,
+			},
+		})
+	}
+
+	return setListResult(d, "storages", entries)
+}
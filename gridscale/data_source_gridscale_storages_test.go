@@ -0,0 +1,68 @@
+package gridscale
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceGridScaleStorages_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckDataSourceGridScaleStoragesConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.gridscale_storages.data", "ids.#", "1"),
+					resource.TestCheckResourceAttr("data.gridscale_storages.data", "storages.0.name", "dstest-data"),
+					resource.TestCheckResourceAttr("data.gridscale_storages.data", "storages.0.capacity", "1"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCheckDataSourceGridScaleStoragesConfig_basic = `
+resource "gridscale_storage" "data" {
+  name = "dstest-data"
+  capacity = 1
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+}
+
+data "gridscale_storages" "data" {
+  name_regex = "^${gridscale_storage.data.name}$"
+}
+`
+
+func TestGetStorages(t *testing.T) {
+	body := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if body == "" {
+			w.WriteHeader(204)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+	api_client := &Config{Endpoint: server.URL}
+
+	storages, err := api_client.getStorages()
+	if err != nil {
+		t.Fatalf("expected no error for an account without storages, got %s", err)
+	}
+	if len(storages) != 0 {
+		t.Fatalf("expected no storages, got %v", storages)
+	}
+
+	body = `{"storages": {"a": {"object_uuid": "a", "name": "data", "capacity": 10}}}`
+	storages, err = api_client.getStorages()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(storages) != 1 || storages[0].ID != "a" || storages[0].Capacity != 10 {
+		t.Fatalf("unexpected storages %+v", storages)
+	}
+}
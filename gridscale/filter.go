@@ -0,0 +1,176 @@
+package gridscale
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// listFilterSchema returns the selection arguments shared by all plural data
+// sources. Objects without a location (like ssh keys) leave out location_uuid.
+func listFilterSchema(withLocation bool) map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"labels": {
+			Type:        schema.TypeSet,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Set:         schema.HashString,
+			Optional:    true,
+			Description: "Only return objects carrying all of these labels.",
+		},
+		"label_map": {
+			Type:        schema.TypeMap,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Optional:    true,
+			Description: "Only return objects carrying all of these key=value labels.",
+		},
+		"label_keys": {
+			Type:        schema.TypeSet,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Set:         schema.HashString,
+			Optional:    true,
+			Description: "Only return objects carrying a key=value label for each of these keys, whatever the value.",
+		},
+		"name_regex": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validateRegex,
+			Description:  "Only return objects whose name matches this regular expression.",
+		},
+		"ids": {
+			Type:     schema.TypeList,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+			Computed: true,
+		},
+	}
+	if withLocation {
+		s["location_uuid"] = &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Only return objects in this location.",
+		}
+	}
+	return s
+}
+
+func validateRegex(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := regexp.Compile(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%q is not a valid regular expression: %s", k, err))
+	}
+	return
+}
+
+// listFilter holds the selection criteria of a plural data source.
+type listFilter struct {
+	labels    []string
+	labelMap  map[string]string
+	labelKeys []string
+	nameRegex *regexp.Regexp
+	location  string
+}
+
+func newListFilter(d *schema.ResourceData) (*listFilter, error) {
+	f := &listFilter{}
+	if v, ok := d.GetOk("labels"); ok {
+		for _, l := range v.(*schema.Set).List() {
+			f.labels = append(f.labels, l.(string))
+		}
+	}
+	if v, ok := d.GetOk("label_map"); ok {
+		f.labelMap = toStringMap(v.(map[string]interface{}))
+	}
+	if v, ok := d.GetOk("label_keys"); ok {
+		f.labelKeys = toStringList(v.(*schema.Set).List())
+	}
+	if v, ok := d.GetOk("name_regex"); ok {
+		re, err := regexp.Compile(v.(string))
+		if err != nil {
+			return nil, err
+		}
+		f.nameRegex = re
+	}
+	if v, ok := d.GetOk("location_uuid"); ok {
+		f.location = v.(string)
+	}
+	return f, nil
+}
+
+// match reports whether an object with the given name, location and labels
+// passes the filter.
+func (f *listFilter) match(name, locationID string, labels []string) bool {
+	if f.location != "" && f.location != locationID {
+		return false
+	}
+	if f.nameRegex != nil && !f.nameRegex.MatchString(name) {
+		return false
+	}
+	for _, want := range f.labels {
+		if !containsString(labels, want) {
+			return false
+		}
+	}
+	for key, value := range f.labelMap {
+		if !containsString(labels, key+"="+value) {
+			return false
+		}
+	}
+	for _, key := range f.labelKeys {
+		if !hasLabelKey(labels, key) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasLabelKey(labels []string, key string) bool {
+	for _, label := range labels {
+		if k, _, ok := parseLabel(label); ok && k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// listEntry is one object returned by a plural data source.
+type listEntry struct {
+	id    string
+	name  string
+	attrs map[string]interface{}
+}
+
+// setListResult stores the matched objects sorted by name and ID, so the
+// result does not depend on the order of the API response.
+func setListResult(d *schema.ResourceData, key string, entries []listEntry) error {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].name != entries[j].name {
+			return entries[i].name < entries[j].name
+		}
+		return entries[i].id < entries[j].id
+	})
+
+	ids := make([]string, 0, len(entries))
+	objects := make([]map[string]interface{}, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.id)
+		objects = append(objects, e.attrs)
+	}
+
+	d.SetId(strconv.Itoa(hashcode.String(strings.Join(ids, ","))))
+	if err := d.Set("ids", ids); err != nil {
+		return err
+	}
+	return d.Set(key, objects)
+}
@@ -0,0 +1,109 @@
+package gridscale
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestListFilter_match(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, listFilterSchema(true), map[string]interface{}{
+		"labels":        []interface{}{"role=web", "env=prod"},
+		"name_regex":    "^web-",
+		"location_uuid": "loc-1",
+	})
+	filter, err := newListFilter(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	cases := []struct {
+		name     string
+		location string
+		labels   []string
+		expected bool
+	}{
+		{"web-1", "loc-1", []string{"env=prod", "role=web", "extra"}, true},
+		{"web-1", "loc-2", []string{"env=prod", "role=web"}, false},
+		{"db-1", "loc-1", []string{"env=prod", "role=web"}, false},
+		{"web-1", "loc-1", []string{"role=web"}, false},
+		{"web-1", "loc-1", nil, false},
+	}
+	for _, tc := range cases {
+		if got := filter.match(tc.name, tc.location, tc.labels); got != tc.expected {
+			t.Errorf("match(%q, %q, %v) = %t, expected %t", tc.name, tc.location, tc.labels, got, tc.expected)
+		}
+	}
+}
+
+func TestListFilter_labelMap(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, listFilterSchema(false), map[string]interface{}{
+		"label_map":  map[string]interface{}{"env": "prod"},
+		"label_keys": []interface{}{"owner"},
+	})
+	filter, err := newListFilter(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	cases := []struct {
+		labels   []string
+		expected bool
+	}{
+		{[]string{"env=prod", "owner=payments"}, true},
+		{[]string{"env=prod", "owner="}, true},
+		{[]string{"env=test", "owner=payments"}, false},
+		{[]string{"env=prod", "owner"}, false},
+		{[]string{"env=prod"}, false},
+	}
+	for _, tc := range cases {
+		if got := filter.match("any", "", tc.labels); got != tc.expected {
+			t.Errorf("match(%v) = %t, expected %t", tc.labels, got, tc.expected)
+		}
+	}
+}
+
+func TestListFilter_empty(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, listFilterSchema(false), map[string]interface{}{})
+	filter, err := newListFilter(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !filter.match("anything", "any-location", nil) {
+		t.Fatal("empty filter should match every object")
+	}
+}
+
+func TestSetListResult_sorted(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataSourceGridScaleServers().Schema, map[string]interface{}{})
+	entries := []listEntry{
+		{id: "b", name: "web-2", attrs: map[string]interface{}{"id": "b", "name": "web-2", "labels": []string{"role=web"}}},
+		{id: "a", name: "web-1", attrs: map[string]interface{}{"id": "a", "name": "web-1", "labels": []string{"role=web"}}},
+	}
+	if err := setListResult(d, "servers", entries); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	ids := d.Get("ids").([]interface{})
+	if len(ids) != 2 || ids[0] != "a" || ids[1] != "b" {
+		t.Fatalf("bad ids: %v", ids)
+	}
+	if name := d.Get("servers.1.name").(string); name != "web-2" {
+		t.Fatalf("bad servers.1.name: %s", name)
+	}
+	if label := d.Get("servers.0.labels.0").(string); label != "role=web" {
+		t.Fatalf("bad servers.0.labels.0: %s", label)
+	}
+	if d.Id() == "" {
+		t.Fatal("expected an ID to be set")
+	}
+}
+
+func TestValidateRegex(t *testing.T) {
+	if _, errs := validateRegex("^web-[0-9]+$", "name_regex"); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if _, errs := validateRegex("web-(", "name_regex"); len(errs) == 0 {
+		t.Fatal("expected an error for an invalid expression")
+	}
+}
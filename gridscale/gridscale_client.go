@@ -1,8 +1,15 @@
 package gridscale
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
-	"github.com/parce-iot/gridscale"
+	"net/http"
+	"github.com/gridscale/terraform-provider-gridscale/internal/gridscale"
+	"sync"
 	"time"
 )
 
@@ -12,6 +19,26 @@ type Config struct {
 	AuthToken string
 	UserId    string
 	Timeout   time.Duration
+	// DefaultLabels are added to the labels of every object.
+	DefaultLabels []string
+	// DefaultLocation is a location UUID, IATA code or name, used when a
+	// resource does not set location_uuid.
+	DefaultLocation string
+	// Transport holds the proxy and TLS settings of the provider.
+	Transport transportConfig
+	// CacheTTL is how long list lookups are cached, zero disables the cache.
+	CacheTTL time.Duration
+	// BatchRefresh reads servers, storages and networks from one list call
+	// per object type.
+	BatchRefresh bool
+
+	httpClient          *http.Client
+	cache               *listCache
+	serverLocks         keyedMutex
+	batch               *batchRefresh
+	defaultLocationMu   sync.Mutex
+	defaultLocationSet  bool
+	defaultLocationID   string
 }
 
 
@@ -26,9 +53,610 @@ func (c *Config) CreateClient() error {
 		return err
 	}
 	c.Client = client
+	c.cache = newListCache(c.CacheTTL)
+	if c.BatchRefresh {
+		c.batch = newBatchRefresh()
+	}
+
+	c.httpClient = http.DefaultClient
+	if !c.Transport.isDefault() {
+		transport, err := newTransport(c.Transport)
+		if err != nil {
+			return err
+		}
+		c.httpClient = &http.Client{Transport: transport}
+		c.Client.SetHTTPClient(c.httpClient)
+	}
 
 	log.Printf("[INFO] GridScale Client configured for url: %s", c.Endpoint)
 
 
 	return nil
 }
+
+// apiError is returned by apiCall when the API answers with a non-2xx status.
+type apiError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *apiError) Error() string {
+	switch e.StatusCode {
+	case 400:
+		return fmt.Sprintf("Input data incomplete or invalid: %s", e.Body)
+	case 401:
+		return fmt.Sprintf("Not authorized: %s", e.Body)
+	case 402:
+		return fmt.Sprintf("No valid payment method available: %s", e.Body)
+	case 403:
+		return fmt.Sprintf("Action forbidden: %s", e.Body)
+	case 404:
+		return fmt.Sprintf("Not found: %s", e.Body)
+	case 409:
+		return fmt.Sprintf("Conflict: %s", e.Body)
+	case 424:
+		return fmt.Sprintf("Action not possible - object is in wrong status: %s", e.Body)
+	}
+	return fmt.Sprintf("Unknown Error (%d): %s", e.StatusCode, e.Body)
+}
+
+// apiCall sends a request to the gridscale API for the parts the client
+// library does not cover. body is sent as JSON if not nil, and a JSON
+// response is decoded into out if not nil. Changes to a server go through
+// the server's lock.
+func (c *Config) apiCall(method, path string, body interface{}, out interface{}) error {
+	if serverID, ok := serverOfPath(method, path); ok {
+		return c.withServerLock(serverID, func() error {
+			return c.doAPICall(method, path, body, out)
+		})
+	}
+	return c.doAPICall(method, path, body, out)
+}
+
+func (c *Config) doAPICall(method, path string, body interface{}, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewBuffer(b)
+	}
+
+	req, err := http.NewRequest(method, c.Endpoint+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("X-Auth-UserId", c.UserId)
+	req.Header.Add("X-Auth-Token", c.AuthToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := c.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if method != "GET" {
+		c.cache.invalidatePath(path)
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &apiError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	if out != nil && len(respBody) > 0 {
+		return json.Unmarshal(respBody, out)
+	}
+	return nil
+}
+
+// storageSummary is a storage as returned by the API, with the metadata the
+// client library leaves out.
+type storageSummary struct {
+	ID         string   `json:"object_uuid"`
+	Name       string   `json:"name"`
+	Capacity   int      `json:"capacity"`
+	ParentID   string   `json:"parent_uuid"`
+	LocationID string   `json:"location_uuid"`
+	Labels     []string `json:"labels"`
+	Relations  struct {
+		Servers []gridscale.StorageServerRelation `json:"servers"`
+	} `json:"relations"`
+	objectMetadata
+}
+
+func (c *Config) getStorage(storageID string) (*storageSummary, error) {
+	wrpr := struct {
+		Storage *storageSummary `json:"storage"`
+	}{}
+	if err := c.apiCall("GET", "/objects/storages/"+storageID, nil, &wrpr); err != nil {
+		return nil, err
+	}
+	if wrpr.Storage == nil {
+		return nil, fmt.Errorf("Storage %s not found: empty response", storageID)
+	}
+	return wrpr.Storage, nil
+}
+
+// getStorages returns all storages. The client library's GetStorages fails
+// on the empty response of an account without storages.
+func (c *Config) getStorages() ([]storageSummary, error) {
+	wrpr := struct {
+		Storages map[string]storageSummary `json:"storages"`
+	}{}
+	if err := c.apiCall("GET", "/objects/storages", nil, &wrpr); err != nil {
+		return nil, err
+	}
+	storages := make([]storageSummary, 0, len(wrpr.Storages))
+	for _, storage := range wrpr.Storages {
+		storages = append(storages, storage)
+	}
+	return storages, nil
+}
+
+// networkSummary is a network as returned by the API, with the metadata the
+// client library leaves out.
+type networkSummary struct {
+	ID         string   `json:"object_uuid"`
+	Name       string   `json:"name"`
+	LocationID string   `json:"location_uuid"`
+	Labels     []string `json:"labels"`
+	Relations  struct {
+		Servers []gridscale.NetworkServerRelation `json:"servers"`
+	} `json:"relations"`
+	objectMetadata
+}
+
+func (c *Config) getNetwork(networkID string) (*networkSummary, error) {
+	wrpr := struct {
+		Network *networkSummary `json:"network"`
+	}{}
+	if err := c.apiCall("GET", "/objects/networks/"+networkID, nil, &wrpr); err != nil {
+		return nil, err
+	}
+	if wrpr.Network == nil {
+		return nil, fmt.Errorf("Network %s not found: empty response", networkID)
+	}
+	return wrpr.Network, nil
+}
+
+// IPAddress holds information about an IP address. The client library's
+// GetIP does not tell a missing address from other errors.
+type IPAddress struct {
+	ID         string   `json:"object_uuid"`
+	IP         string   `json:"ip"`
+	Prefix     string   `json:"prefix"`
+	Family     int      `json:"family"`
+	Failover   bool     `json:"failover"`
+	ReverseDNS string   `json:"reverse_dns"`
+	LocationID string   `json:"location_uuid"`
+	Labels     []string `json:"labels"`
+	Relations  struct {
+		Servers []gridscale.IPServerRelation `json:"servers"`
+	} `json:"relations"`
+	objectMetadata
+}
+
+func (c *Config) getIP(ipID string) (*IPAddress, error) {
+	wrpr := struct {
+		IP *IPAddress `json:"ip"`
+	}{}
+	if err := c.apiCall("GET", "/objects/ips/"+ipID, nil, &wrpr); err != nil {
+		return nil, err
+	}
+	if wrpr.IP == nil {
+		return nil, fmt.Errorf("IP %s not found: empty response", ipID)
+	}
+	return wrpr.IP, nil
+}
+
+// serverIPRelation is a public IP address connected to a server.
+type serverIPRelation struct {
+	IPID   string `json:"object_uuid"`
+	IP     string `json:"ip"`
+	Family int    `json:"family"`
+	Prefix string `json:"prefix"`
+}
+
+// serverIsoImageRelation is an ISO image mounted in a server.
+type serverIsoImageRelation struct {
+	IsoImageID string `json:"object_uuid"`
+	Name       string `json:"object_name"`
+	Bootdevice bool   `json:"bootdevice"`
+}
+
+// serverRelations holds the objects connected to a server. The client
+// library decodes the relations of a server into the wrong shape, so they
+// are fetched and decoded here.
+type serverRelations struct {
+	Networks  []gridscale.ServerNetworkRelation `json:"networks"`
+	Storages  []gridscale.ServerStorageRelation `json:"storages"`
+	PublicIPs []serverIPRelation                `json:"public_ips"`
+	IsoImages []serverIsoImageRelation          `json:"isoimages"`
+}
+
+// getServerRelations returns the networks, storages, IPs and ISO images
+// connected to a server.
+// getServer returns a server and its relations from a single request.
+func (c *Config) getServer(serverID string) (*gridscale.Server, *serverRelations, error) {
+	wrpr := struct {
+		Server *batchedServer `json:"server"`
+	}{}
+	if err := c.apiCall("GET", "/objects/servers/"+serverID, nil, &wrpr); err != nil {
+		return nil, nil, err
+	}
+	if wrpr.Server == nil {
+		return nil, nil, fmt.Errorf("Server %s not found: empty response", serverID)
+	}
+	return &wrpr.Server.Server, &wrpr.Server.Relations, nil
+}
+
+func (c *Config) getServerRelations(serverID string) (*serverRelations, error) {
+	wrpr := struct {
+		Server struct {
+			Relations serverRelations `json:"relations"`
+		} `json:"server"`
+	}{}
+	if err := c.apiCall("GET", "/objects/servers/"+serverID, nil, &wrpr); err != nil {
+		return nil, err
+	}
+	return &wrpr.Server.Relations, nil
+}
+
+// getPublicNetwork returns the public network, optionally restricted to a location.
+func (c *Config) getPublicNetwork(locationID string) (*gridscale.Network, error) {
+	networks, err := c.getPublicNetworks()
+	if err != nil {
+		return nil, err
+	}
+	for _, network := range networks {
+		if locationID == "" || network.LocationID == locationID {
+			return &network, nil
+		}
+	}
+	if locationID == "" {
+		return nil, fmt.Errorf("Did not find public network")
+	}
+	return nil, fmt.Errorf("Did not find public network in location %s", locationID)
+}
+
+func isNotFound(err error) bool {
+	e, ok := err.(*apiError)
+	return ok && e.StatusCode == 404
+}
+
+// defaultLocationUUID resolves DefaultLocation to a location UUID. Once
+// found, the UUID is kept for the lifetime of the provider. A failed lookup
+// is not kept, so the next call tries again.
+func (c *Config) defaultLocationUUID() (string, error) {
+	if c.DefaultLocation == "" {
+		return "", nil
+	}
+	c.defaultLocationMu.Lock()
+	defer c.defaultLocationMu.Unlock()
+	if c.defaultLocationSet {
+		return c.defaultLocationID, nil
+	}
+	locations, err := c.getLocations()
+	if err != nil {
+		return "", err
+	}
+	id, err := findLocation(locations, c.DefaultLocation)
+	if err != nil {
+		return "", err
+	}
+	c.defaultLocationID, c.defaultLocationSet = id, true
+	return id, nil
+}
+
+// updateLabels replaces the labels of the object at path. The client library
+// leaves out empty label lists, so it cannot remove the last label.
+func (c *Config) updateLabels(path string, labels []string) error {
+	if labels == nil {
+		labels = []string{}
+	}
+	req := struct {
+		Labels []string `json:"labels"`
+	}{labels}
+	return c.apiCall("PATCH", path, req, nil)
+}
+
+// updateServerStorageRelation changes the boot device flag of a storage
+// connected to a server.
+func (c *Config) updateServerStorageRelation(serverID, storageID string, bootdevice bool) error {
+	body := map[string]bool{"bootdevice": bootdevice}
+	return c.apiCall("PATCH", "/objects/servers/"+serverID+"/storages/"+storageID, body, nil)
+}
+
+// updateServerNetworkRelation changes the ordering of a network connected to a server.
+func (c *Config) updateServerNetworkRelation(serverID, networkID string, ordering int) error {
+	body := map[string]int{"ordering": ordering}
+	return c.apiCall("PATCH", "/objects/servers/"+serverID+"/networks/"+networkID, body, nil)
+}
+
+// IsoImage holds information about an ISO image.
+type IsoImage struct {
+	ID         string   `json:"object_uuid"`
+	Name       string   `json:"name"`
+	SourceURL  string   `json:"source_url"`
+	Labels     []string `json:"labels"`
+	Status     string   `json:"status"`
+	Capacity   int      `json:"capacity"`
+	LocationID string   `json:"location_uuid"`
+}
+
+type createIsoImageRequest struct {
+	Name       string   `json:"name"`
+	SourceURL  string   `json:"source_url"`
+	Labels     []string `json:"labels,omitempty"`
+	LocationID string   `json:"location_uuid"`
+}
+
+type patchIsoImageRequest struct {
+	Name   string   `json:"name,omitempty"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+type createObjectResponse struct {
+	ID string `json:"object_uuid"`
+}
+
+// createIsoImage registers an ISO image downloaded from sourceURL.
+func (c *Config) createIsoImage(locationID, name, sourceURL string, labels []string) (string, error) {
+	req := createIsoImageRequest{
+		Name:       name,
+		SourceURL:  sourceURL,
+		Labels:     labels,
+		LocationID: locationID,
+	}
+	resp := createObjectResponse{}
+	if err := c.apiCall("POST", "/objects/isoimages", req, &resp); err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+func (c *Config) getIsoImage(isoImageID string) (*IsoImage, error) {
+	wrpr := struct {
+		IsoImage *IsoImage `json:"isoimage"`
+	}{}
+	if err := c.apiCall("GET", "/objects/isoimages/"+isoImageID, nil, &wrpr); err != nil {
+		return nil, err
+	}
+	if wrpr.IsoImage == nil {
+		return nil, fmt.Errorf("ISO image %s not found: empty response", isoImageID)
+	}
+	return wrpr.IsoImage, nil
+}
+
+func (c *Config) updateIsoImage(isoImageID string, p patchIsoImageRequest) error {
+	return c.apiCall("PATCH", "/objects/isoimages/"+isoImageID, p, nil)
+}
+
+func (c *Config) deleteIsoImage(isoImageID string) error {
+	return c.apiCall("DELETE", "/objects/isoimages/"+isoImageID, nil, nil)
+}
+
+// Snapshot holds information about a storage snapshot.
+type Snapshot struct {
+	ID         string    `json:"object_uuid"`
+	Name       string    `json:"name"`
+	Labels     []string  `json:"labels"`
+	Status     string    `json:"status"`
+	Capacity   int       `json:"capacity"`
+	LocationID string    `json:"location_uuid"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+type snapshotRequest struct {
+	Name   string   `json:"name,omitempty"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+func snapshotPath(storageID string) string {
+	return "/objects/storages/" + storageID + "/snapshots"
+}
+
+// createSnapshot takes a snapshot of a storage.
+func (c *Config) createSnapshot(storageID, name string, labels []string) (string, error) {
+	resp := createObjectResponse{}
+	err := c.apiCall("POST", snapshotPath(storageID), snapshotRequest{Name: name, Labels: labels}, &resp)
+	if err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+func (c *Config) getSnapshot(storageID, snapshotID string) (*Snapshot, error) {
+	wrpr := struct {
+		Snapshot *Snapshot `json:"snapshot"`
+	}{}
+	if err := c.apiCall("GET", snapshotPath(storageID)+"/"+snapshotID, nil, &wrpr); err != nil {
+		return nil, err
+	}
+	if wrpr.Snapshot == nil {
+		return nil, fmt.Errorf("Snapshot %s not found: empty response", snapshotID)
+	}
+	return wrpr.Snapshot, nil
+}
+
+// getSnapshots returns all snapshots of a storage.
+func (c *Config) getSnapshots(storageID string) ([]Snapshot, error) {
+	wrpr := struct {
+		Snapshots map[string]Snapshot `json:"snapshots"`
+	}{}
+	if err := c.apiCall("GET", snapshotPath(storageID), nil, &wrpr); err != nil {
+		return nil, err
+	}
+	snapshots := []Snapshot{}
+	for _, snapshot := range wrpr.Snapshots {
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+func (c *Config) updateSnapshot(storageID, snapshotID, name string, labels []string) error {
+	return c.apiCall("PATCH", snapshotPath(storageID)+"/"+snapshotID, snapshotRequest{Name: name, Labels: labels}, nil)
+}
+
+func (c *Config) deleteSnapshot(storageID, snapshotID string) error {
+	return c.apiCall("DELETE", snapshotPath(storageID)+"/"+snapshotID, nil, nil)
+}
+
+// cloneStorage creates a copy of a storage and returns the ID of the copy.
+func (c *Config) cloneStorage(storageID string) (string, error) {
+	resp := createObjectResponse{}
+	if err := c.apiCall("POST", "/objects/storages/"+storageID+"/clone", nil, &resp); err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+// cloneSnapshot creates a new storage from a snapshot and returns its ID.
+func (c *Config) cloneSnapshot(storageID, snapshotID string) (string, error) {
+	resp := createObjectResponse{}
+	if err := c.apiCall("POST", snapshotPath(storageID)+"/"+snapshotID+"/clone", nil, &resp); err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+// findSnapshotStorage returns the ID of the storage a snapshot belongs to.
+func (c *Config) findSnapshotStorage(snapshotID string) (string, error) {
+	storages, err := c.GetStorages()
+	if err != nil {
+		return "", err
+	}
+	for _, storage := range storages {
+		for _, snapshot := range storage.Snapshots {
+			if snapshot.ID == snapshotID {
+				return storage.ID, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("Could not find the storage of snapshot %s", snapshotID)
+}
+
+// rollbackSnapshot resets a storage to the state of one of its snapshots.
+func (c *Config) rollbackSnapshot(storageID, snapshotID string) error {
+	body := map[string]bool{"rollback": true}
+	return c.apiCall("PATCH", snapshotPath(storageID)+"/"+snapshotID+"/rollback", body, nil)
+}
+
+// SnapshotSchedule holds information about a scheduled snapshot policy.
+type SnapshotSchedule struct {
+	ID            string    `json:"object_uuid"`
+	Name          string    `json:"name"`
+	Labels        []string  `json:"labels"`
+	Status        string    `json:"status"`
+	RunInterval   int       `json:"run_interval"`
+	KeepSnapshots int       `json:"keep_snapshots"`
+	NextRuntime   time.Time `json:"next_runtime"`
+	Relations     struct {
+		Snapshots []SnapshotScheduleSnapshot `json:"snapshots"`
+	} `json:"relations"`
+}
+
+// SnapshotScheduleSnapshot is a snapshot taken and retained by a schedule.
+type SnapshotScheduleSnapshot struct {
+	ID         string    `json:"object_uuid"`
+	Name       string    `json:"name"`
+	CreateTime time.Time `json:"create_time"`
+}
+
+type snapshotScheduleRequest struct {
+	Name          string     `json:"name,omitempty"`
+	Labels        []string   `json:"labels,omitempty"`
+	RunInterval   int        `json:"run_interval,omitempty"`
+	KeepSnapshots int        `json:"keep_snapshots,omitempty"`
+	NextRuntime   *time.Time `json:"next_runtime,omitempty"`
+}
+
+func snapshotSchedulePath(storageID string) string {
+	return "/objects/storages/" + storageID + "/snapshot_schedules"
+}
+
+func (c *Config) createSnapshotSchedule(storageID string, req snapshotScheduleRequest) (string, error) {
+	resp := createObjectResponse{}
+	if err := c.apiCall("POST", snapshotSchedulePath(storageID), req, &resp); err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+func (c *Config) getSnapshotSchedule(storageID, scheduleID string) (*SnapshotSchedule, error) {
+	wrpr := struct {
+		Schedule *SnapshotSchedule `json:"snapshot_schedule"`
+	}{}
+	if err := c.apiCall("GET", snapshotSchedulePath(storageID)+"/"+scheduleID, nil, &wrpr); err != nil {
+		return nil, err
+	}
+	if wrpr.Schedule == nil {
+		return nil, fmt.Errorf("Snapshot schedule %s not found: empty response", scheduleID)
+	}
+	return wrpr.Schedule, nil
+}
+
+func (c *Config) updateSnapshotSchedule(storageID, scheduleID string, req snapshotScheduleRequest) error {
+	return c.apiCall("PATCH", snapshotSchedulePath(storageID)+"/"+scheduleID, req, nil)
+}
+
+func (c *Config) deleteSnapshotSchedule(storageID, scheduleID string) error {
+	return c.apiCall("DELETE", snapshotSchedulePath(storageID)+"/"+scheduleID, nil, nil)
+}
+
+type templateRequest struct {
+	Name       string   `json:"name,omitempty"`
+	SnapshotID string   `json:"snapshot_uuid,omitempty"`
+	Labels     []string `json:"labels,omitempty"`
+	OSType     string   `json:"ostype,omitempty"`
+	Version    string   `json:"version,omitempty"`
+}
+
+// createTemplate creates a private template from a snapshot.
+func (c *Config) createTemplate(req templateRequest) (string, error) {
+	resp := createObjectResponse{}
+	if err := c.apiCall("POST", "/objects/templates", req, &resp); err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+// Template holds information about a template. The client library's type
+// lacks the labels.
+type Template struct {
+	gridscale.Template
+	Labels []string `json:"labels"`
+}
+
+func (c *Config) getTemplate(templateID string) (*Template, error) {
+	wrpr := struct {
+		Template *Template `json:"template"`
+	}{}
+	if err := c.apiCall("GET", "/objects/templates/"+templateID, nil, &wrpr); err != nil {
+		return nil, err
+	}
+	if wrpr.Template == nil {
+		return nil, fmt.Errorf("Template %s not found: empty response", templateID)
+	}
+	return wrpr.Template, nil
+}
+
+func (c *Config) updateTemplate(templateID string, req templateRequest) error {
+	return c.apiCall("PATCH", "/objects/templates/"+templateID, req, nil)
+}
+
+func (c *Config) deleteTemplate(templateID string) error {
+	return c.apiCall("DELETE", "/objects/templates/"+templateID, nil, nil)
+}
@@ -0,0 +1,69 @@
+package gridscale
+
+import (
+	"time"
+
+	"github.com/gridscale/terraform-provider-gridscale/internal/gridscale"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// objectMetadata holds the informational fields the API returns for every
+// object. The client library leaves most of them out for storages, networks
+// and IPs, so they are decoded here.
+type objectMetadata struct {
+	Status          string    `json:"status"`
+	CreateTime      time.Time `json:"create_time"`
+	ChangeTime      time.Time `json:"change_time"`
+	CurrentPrice    float64   `json:"current_price"`
+	LocationName    string    `json:"location_name"`
+	LocationIata    string    `json:"location_iata"`
+	LocationCountry string    `json:"location_country"`
+}
+
+// withMetadataSchema adds the computed metadata attributes to a resource
+// schema.
+func withMetadataSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	for _, key := range []string{"status", "create_time", "change_time", "location_name", "location_iata", "location_country"} {
+		s[key] = &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		}
+	}
+	s["current_price"] = &schema.Schema{
+		Type:        schema.TypeFloat,
+		Computed:    true,
+		Description: "Price of the object so far in the current billing period.",
+	}
+	return s
+}
+
+func setObjectMetadata(d *schema.ResourceData, m *objectMetadata) {
+	d.Set("status", m.Status)
+	d.Set("create_time", formatTime(m.CreateTime))
+	d.Set("change_time", formatTime(m.ChangeTime))
+	d.Set("current_price", m.CurrentPrice)
+	d.Set("location_name", m.LocationName)
+	d.Set("location_iata", m.LocationIata)
+	d.Set("location_country", m.LocationCountry)
+}
+
+// formatTime formats t as RFC 3339, or returns "" if the API left it out.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func serverMetadata(server *gridscale.Server) *objectMetadata {
+	price, _ := server.CurrentPrice.Float64()
+	return &objectMetadata{
+		Status:          server.Status,
+		CreateTime:      server.CreateTime,
+		ChangeTime:      server.ChangeTime,
+		CurrentPrice:    price,
+		LocationName:    server.LocationName,
+		LocationIata:    server.LocationIata,
+		LocationCountry: server.LocationCountry,
+	}
+}
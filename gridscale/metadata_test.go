@@ -0,0 +1,75 @@
+package gridscale
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gridscale/terraform-provider-gridscale/internal/gridscale"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestReadObjectMetadata(t *testing.T) {
+	metadata := `"status": "active",
+		"create_time": "2018-04-10T09:15:32Z",
+		"change_time": "2018-04-11T12:00:00Z",
+		"current_price": 0.12,
+		"location_name": "de/fra",
+		"location_iata": "fra",
+		"location_country": "de"`
+	requests := map[string]int{}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/objects/storages/storage-uuid":
+			w.Write([]byte(`{"storage": {"object_uuid": "storage-uuid", "capacity": 10, ` + metadata + `}}`))
+		case "/objects/networks/network-uuid":
+			w.Write([]byte(`{"network": {"object_uuid": "network-uuid", ` + metadata + `}}`))
+		case "/objects/ips/ip-uuid":
+			w.Write([]byte(`{"ip": {"object_uuid": "ip-uuid", "ip": "185.1.2.3", ` + metadata + `}}`))
+		case "/prices":
+			w.Write([]byte(`{"prices": []}`))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer api.Close()
+	client, _ := gridscale.NewClient("user-uuid", "token", api.URL)
+	api_client := &Config{Client: client, Endpoint: api.URL}
+
+	cases := []struct {
+		resource *schema.Resource
+		read     schema.ReadFunc
+		id, path string
+	}{
+		{resourceGridScaleStorage(), resourceGridScaleStorageRead, "storage-uuid", "/objects/storages/storage-uuid"},
+		{resourceGridScaleNetwork(), resourceGridScaleNetworkRead, "network-uuid", "/objects/networks/network-uuid"},
+		{resourceGridScaleIP(), resourceGridScaleIPRead, "ip-uuid", "/objects/ips/ip-uuid"},
+	}
+	for _, tc := range cases {
+		d := schema.TestResourceDataRaw(t, tc.resource.Schema, map[string]interface{}{})
+		d.SetId(tc.id)
+		if err := tc.read(d, api_client); err != nil {
+			t.Fatalf("%s: %s", tc.id, err)
+		}
+		if d.Get("status").(string) != "active" || d.Get("current_price").(float64) != 0.12 ||
+			d.Get("location_iata").(string) != "fra" || d.Get("create_time").(string) != "2018-04-10T09:15:32Z" {
+			t.Fatalf("%s: unexpected metadata %v", tc.id, d.State().Attributes)
+		}
+		// The metadata comes from the response Read already has.
+		if requests[tc.path] != 1 {
+			t.Fatalf("%s: expected one request, got %d", tc.id, requests[tc.path])
+		}
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	if got := formatTime(time.Time{}); got != "" {
+		t.Fatalf("formatTime(zero) = %q, want empty", got)
+	}
+	ts := time.Date(2018, 4, 10, 9, 15, 32, 0, time.UTC)
+	if got := formatTime(ts); got != "2018-04-10T09:15:32Z" {
+		t.Fatalf("formatTime = %q", got)
+	}
+}
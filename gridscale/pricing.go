@@ -0,0 +1,107 @@
+package gridscale
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gridscale/terraform-provider-gridscale/internal/gridscale"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// hoursPerMonth is the average number of hours in a month, as used for
+// monthly estimates.
+const hoursPerMonth = 730
+
+// Price types, as returned by the prices endpoint, that the estimates are
+// built from.
+var (
+	corePriceTypes    = []string{"core", "cpu"}
+	memoryPriceTypes  = []string{"memory", "ram"}
+	storagePriceTypes = []string{"storage"}
+)
+
+// hourlyPrice returns the price of one unit of a product for one hour. The
+// billing period is taken from the end of the unit, e.g. "GB/minute".
+// Units without a known period are taken as hourly.
+func hourlyPrice(p gridscale.Price) float64 {
+	price, _ := p.PricePerUnit.Float64()
+	unit := strings.ToLower(p.Unit)
+	switch {
+	case strings.HasSuffix(unit, "minute"), strings.HasSuffix(unit, "min"):
+		return price * 60
+	case strings.HasSuffix(unit, "day"):
+		return price / 24
+	case strings.HasSuffix(unit, "month"):
+		return price / hoursPerMonth
+	}
+	return price
+}
+
+// findPrice returns the first price whose type is one of types.
+func findPrice(prices []gridscale.Price, types []string) (gridscale.Price, error) {
+	for _, p := range prices {
+		for _, t := range types {
+			if strings.EqualFold(p.Type, t) {
+				return p, nil
+			}
+		}
+	}
+	return gridscale.Price{}, fmt.Errorf("No price found for type %s", strings.Join(types, " or "))
+}
+
+// estimateServerCost returns the hourly price of a server with the given
+// number of cores and GB of memory.
+func estimateServerCost(prices []gridscale.Price, cores, memory int) (float64, error) {
+	core, err := findPrice(prices, corePriceTypes)
+	if err != nil {
+		return 0, err
+	}
+	mem, err := findPrice(prices, memoryPriceTypes)
+	if err != nil {
+		return 0, err
+	}
+	return float64(cores)*hourlyPrice(core) + float64(memory)*hourlyPrice(mem), nil
+}
+
+// estimateStorageCost returns the hourly price of a storage with the given
+// capacity in GB.
+func estimateStorageCost(prices []gridscale.Price, capacity int) (float64, error) {
+	storage, err := findPrice(prices, storagePriceTypes)
+	if err != nil {
+		return 0, err
+	}
+	return float64(capacity) * hourlyPrice(storage), nil
+}
+
+// withEstimatedCostSchema adds the computed cost estimates to a resource
+// schema.
+func withEstimatedCostSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	s["estimated_hourly_cost"] = &schema.Schema{
+		Type:        schema.TypeFloat,
+		Computed:    true,
+		Description: "Estimated price per hour, from the list prices.",
+	}
+	s["estimated_monthly_cost"] = &schema.Schema{
+		Type:        schema.TypeFloat,
+		Computed:    true,
+		Description: "Estimated price per month of 730 hours, from the list prices.",
+	}
+	return s
+}
+
+// readEstimatedCost sets the cost estimates from estimate. A failure only
+// leaves the estimates out, it does not fail the refresh.
+func readEstimatedCost(d *schema.ResourceData, api_client *Config, estimate func([]gridscale.Price) (float64, error)) {
+	prices, err := api_client.getPrices()
+	if err == nil {
+		var hourly float64
+		hourly, err = estimate(prices)
+		if err == nil {
+			d.Set("estimated_hourly_cost", hourly)
+			d.Set("estimated_monthly_cost", hourly*hoursPerMonth)
+			return
+		}
+	}
+	log.Printf("[WARN] Could not estimate the cost of %s: %s", d.Id(), err)
+}
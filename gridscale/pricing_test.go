@@ -0,0 +1,59 @@
+package gridscale
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gridscale/terraform-provider-gridscale/internal/gridscale"
+	"github.com/shopspring/decimal"
+)
+
+func testPrice(priceType, unit string, price float64) gridscale.Price {
+	return gridscale.Price{Type: priceType, Unit: unit, PricePerUnit: decimal.NewFromFloat(price)}
+}
+
+func TestHourlyPrice(t *testing.T) {
+	cases := []struct {
+		unit string
+		want float64
+	}{
+		{"Core/hour", 0.5},
+		{"GB/minute", 30},
+		{"GB/day", 0.5 / 24},
+		{"GB/month", 0.5 / hoursPerMonth},
+		{"", 0.5},
+	}
+	for _, c := range cases {
+		if got := hourlyPrice(testPrice("core", c.unit, 0.5)); math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("hourlyPrice(%q) = %v, want %v", c.unit, got, c.want)
+		}
+	}
+}
+
+func TestEstimateCost(t *testing.T) {
+	prices := []gridscale.Price{
+		testPrice("Core", "Core/hour", 0.01),
+		testPrice("Memory", "GB/hour", 0.005),
+		testPrice("Storage", "GB/hour", 0.0001),
+	}
+
+	hourly, err := estimateServerCost(prices, 2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(hourly-0.04) > 1e-9 {
+		t.Fatalf("server estimate = %v, want 0.04", hourly)
+	}
+
+	hourly, err = estimateStorageCost(prices, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(hourly-0.01) > 1e-9 {
+		t.Fatalf("storage estimate = %v, want 0.01", hourly)
+	}
+
+	if _, err := estimateServerCost(prices[2:], 2, 4); err == nil {
+		t.Fatal("expected an error without core and memory prices")
+	}
+}
@@ -1,6 +1,8 @@
 package gridscale
 
 import (
+	"time"
+
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 )
@@ -11,28 +13,125 @@ func Provider() terraform.ResourceProvider {
 		Schema: map[string]*schema.Schema{
 			"api_url": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("GRIDSCALE_API_URL", nil),
 				Description: "",
 			},
 			"api_token": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
+				Sensitive:   true,
 				DefaultFunc: schema.EnvDefaultFunc("GRIDSCALE_API_TOKEN", nil),
 				Description: "",
 			},
 			"user_uuid": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("GRIDSCALE_USER_UUID", nil),
 				Description: "",
 			},
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GRIDSCALE_PROFILE", nil),
+				Description: "Profile in the config file to read credentials from. Defaults to \"default\" if that profile exists.",
+			},
+			"config_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GRIDSCALE_CONFIG_FILE", nil),
+				Description: "Path of the config file with the profiles. Defaults to ~/.gridscale/config.",
+			},
+			"api_token_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GRIDSCALE_API_TOKEN_FILE", nil),
+				Description: "File to read the API token from.",
+			},
+			"credential_process": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Command printing the credentials as JSON with api_url, user_uuid and api_token.",
+			},
+			"proxy_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GRIDSCALE_PROXY_URL", nil),
+				Description: "HTTP proxy for all API calls, credentials may be given in the URL. Defaults to the proxy environment variables.",
+			},
+			"ca_cert_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GRIDSCALE_CA_CERT_FILE", nil),
+				Description: "PEM file with additional CA certificates to trust.",
+			},
+			"client_cert": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "PEM client certificate, or the path of a file holding it.",
+			},
+			"client_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "PEM key of client_cert, or the path of a file holding it.",
+			},
+			"insecure": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Skip the verification of the API's TLS certificate. Only meant for testing.",
+			},
+			"cache_ttl": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     300,
+				Description: "Seconds to cache templates, locations, prices and public networks. 0 disables the cache.",
+			},
+			"batch_refresh": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Read servers, storages and networks from one list call per type instead of one call per object.",
+			},
+			"default_location": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GRIDSCALE_LOCATION", nil),
+				Description: "Location UUID, IATA code or name used by resources that do not set location_uuid.",
+			},
+			"default_labels": {
+				Type:        schema.TypeList,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Optional:    true,
+				Description: "Labels added to every object created or updated by the provider.",
+			},
+		},
+
+		DataSourcesMap: map[string]*schema.Resource{
+			"gridscale_servers":        dataSourceGridScaleServers(),
+			"gridscale_storages":       dataSourceGridScaleStorages(),
+			"gridscale_networks":       dataSourceGridScaleNetworks(),
+			"gridscale_ips":            dataSourceGridScaleIPs(),
+			"gridscale_sshkeys":        dataSourceGridScaleSSHKeys(),
+			"gridscale_public_network": dataSourceGridScalePublicNetwork(),
+			"gridscale_snapshots":      dataSourceGridScaleSnapshots(),
+			"gridscale_prices":         dataSourceGridScalePrices(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
 			"gridscale_server": resourceGridScaleServer(),
 			"gridscale_network": resourceGridScaleNetwork(),
 			"gridscale_storage": resourceGridScaleStorage(),
+			"gridscale_ip": resourceGridScaleIP(),
+			"gridscale_iso_image": resourceGridScaleIsoImage(),
+			"gridscale_snapshot": resourceGridScaleSnapshot(),
+			"gridscale_snapshot_rollback": resourceGridScaleSnapshotRollback(),
+			"gridscale_snapshot_schedule": resourceGridScaleSnapshotSchedule(),
+			"gridscale_template": resourceGridScaleTemplate(),
+			"gridscale_server_ip_attachment": resourceGridScaleServerIPAttachment(),
+			"gridscale_server_storage_attachment": resourceGridScaleServerStorageAttachment(),
+			"gridscale_server_network_attachment": resourceGridScaleServerNetworkAttachment(),
 		},
 
 		ConfigureFunc: providerConfigure,
@@ -40,13 +139,35 @@ func Provider() terraform.ResourceProvider {
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	creds, err := resolveCredentials(credentials{
+		APIURL:            d.Get("api_url").(string),
+		UserUUID:          d.Get("user_uuid").(string),
+		APIToken:          d.Get("api_token").(string),
+		APITokenFile:      d.Get("api_token_file").(string),
+		CredentialProcess: d.Get("credential_process").(string),
+	}, d.Get("config_file").(string), d.Get("profile").(string))
+	if err != nil {
+		return nil, err
+	}
+
 	config := &Config{
-		Endpoint:  d.Get("api_url").(string),
-		AuthToken: d.Get("api_token").(string),
-		UserId:    d.Get("user_uuid").(string),
+		Endpoint:  creds.APIURL,
+		AuthToken: creds.APIToken,
+		UserId:    creds.UserUUID,
+		DefaultLabels: toStringList(d.Get("default_labels").([]interface{})),
+		DefaultLocation: d.Get("default_location").(string),
+		CacheTTL:        time.Duration(d.Get("cache_ttl").(int)) * time.Second,
+		BatchRefresh:    d.Get("batch_refresh").(bool),
+		Transport: transportConfig{
+			ProxyURL:   d.Get("proxy_url").(string),
+			CACertFile: d.Get("ca_cert_file").(string),
+			ClientCert: d.Get("client_cert").(string),
+			ClientKey:  d.Get("client_key").(string),
+			Insecure:   d.Get("insecure").(bool),
+		},
 	}
 
-	err := config.CreateClient()
+	err = config.CreateClient()
 
 	return config, err
 }
@@ -0,0 +1,178 @@
+package gridscale
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceGridScaleIP() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGridScaleIPCreate,
+		Read:   resourceGridScaleIPRead,
+		Update: resourceGridScaleIPUpdate,
+		Delete: resourceGridScaleIPDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: withMetadataSchema(map[string]*schema.Schema{
+			"location_uuid": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Defaults to the provider's default_location.",
+			},
+			"family": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      4,
+				ValidateFunc: validateIPFamily,
+			},
+			"failover": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"reverse_dns": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"labels":                 labelsSchema(),
+			"default_labels_applied": defaultLabelsAppliedSchema(),
+			"label_map":              labelMapSchema(),
+			"delete_protection":      deleteProtectionSchema(),
+			"ip": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"prefix": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		}),
+	}
+}
+
+func validateIPFamily(v interface{}, k string) (ws []string, errors []error) {
+	if family := v.(int); family != 4 && family != 6 {
+		errors = append(errors, fmt.Errorf("%q must be 4 or 6, got %d", k, family))
+	}
+	return
+}
+
+func resourceGridScaleIPCreate(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	if err := setResourceLocation(d, api_client); err != nil {
+		return err
+	}
+
+	var reverseDNS *string
+	if v, ok := d.GetOk("reverse_dns"); ok {
+		s := v.(string)
+		reverseDNS = &s
+	}
+
+	create := api_client.CreateIPv4
+	if d.Get("family").(int) == 6 {
+		create = api_client.CreateIPv6
+	}
+	ip, err := create(
+		d.Get("location_uuid").(string),
+		d.Get("failover").(bool),
+		objectLabels(d, api_client),
+		reverseDNS,
+	)
+	if err != nil {
+		return err
+	}
+	d.SetId(ip.ID)
+	return resourceGridScaleIPRead(d, meta)
+}
+
+func resourceGridScaleIPRead(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	ipId := d.Id()
+
+	ip, err := api_client.getIP(ipId)
+	if isNotFound(err) {
+		log.Printf("[WARN] IP %s not found, removing from state", ipId)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("location_uuid", ip.LocationID)
+	d.Set("family", ip.Family)
+	d.Set("failover", ip.Failover)
+	d.Set("reverse_dns", ip.ReverseDNS)
+	protected, err := readObjectLabels(d, api_client, ip.Labels)
+	if err != nil {
+		return err
+	}
+	d.Set("delete_protection", protected)
+	d.Set("ip", ip.IP)
+	d.Set("prefix", ip.Prefix)
+	setObjectMetadata(d, &ip.objectMetadata)
+	return nil
+}
+
+func resourceGridScaleIPUpdate(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	ipId := d.Id()
+
+	if d.HasChange("failover") {
+		if err := api_client.UpdateIPFailover(ipId, d.Get("failover").(bool)); err != nil {
+			return err
+		}
+	}
+	if d.HasChange("reverse_dns") {
+		if err := api_client.UpdateIPReverseDNS(ipId, d.Get("reverse_dns").(string)); err != nil {
+			return err
+		}
+	}
+	if err := updateObjectLabels(d, api_client, "/objects/ips/"+ipId); err != nil {
+		return err
+	}
+
+	return resourceGridScaleIPRead(d, meta)
+}
+
+func resourceGridScaleIPDelete(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	ipId := d.Id()
+
+	ip, err := api_client.getIP(ipId)
+	if isNotFound(err) {
+		log.Printf("[WARN] IP %s not found, removing from state", ipId)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkDeleteProtection("IP "+ipId, ip.Labels); err != nil {
+		return err
+	}
+	// IP addresses can be disconnected from running servers.
+	for _, rel := range ip.Relations.Servers {
+		if err := api_client.DisconnectIPAddress(ipId, rel.ServerID); err != nil {
+			return err
+		}
+	}
+
+	if err := deleteObject(api_client, "/objects/ips/"+ipId, d.Timeout(schema.TimeoutDelete)); err != nil {
+		return err
+	}
+	d.SetId("")
+
+	return nil
+}
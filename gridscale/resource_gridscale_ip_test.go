@@ -0,0 +1,139 @@
+package gridscale
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gridscale/terraform-provider-gridscale/internal/gridscale"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGridScaleIP_Basic(t *testing.T) {
+	var ip gridscale.IP
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDGridScaleIPDestroyCheck,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckGridScaleIPConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGridScaleIPExists("gridscale_ip.testip", &ip),
+					resource.TestCheckResourceAttr("gridscale_ip.testip", "family", "4"),
+					resource.TestCheckResourceAttr("gridscale_ip.testip", "failover", "false"),
+					resource.TestCheckResourceAttrSet("gridscale_ip.testip", "ip"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckGridScaleIPConfig_update,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gridscale_ip.testip", "failover", "true"),
+					resource.TestCheckResourceAttr("gridscale_ip.testip", "reverse_dns", "test.example.com"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDGridScaleIPDestroyCheck(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Config)
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gridscale_ip" {
+			continue
+		}
+		ip, _ := client.GetIP(rs.Primary.ID)
+		if ip == nil {
+			continue
+		}
+		err := client.DeleteIP(rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("IP %s was not deleted: error to %s", rs.Primary.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckGridScaleIPExists(n string, ip *gridscale.IP) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Config)
+
+		rs, ok := s.RootModule().Resources[n]
+
+		if !ok {
+			return fmt.Errorf("testAccCheckGridScaleIPExists: Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No Record ID is set")
+		}
+
+		foundIP, err := client.GetIP(rs.Primary.ID)
+
+		if err != nil {
+			return fmt.Errorf("Error occured while fetching IP: %s", rs.Primary.ID)
+		}
+		if foundIP.ID != rs.Primary.ID {
+			return fmt.Errorf("Record not found")
+		}
+		*ip = *foundIP
+
+		return nil
+	}
+}
+
+const testAccCheckGridScaleIPConfig_basic = `
+resource "gridscale_ip" "testip" {
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+}`
+
+const testAccCheckGridScaleIPConfig_update = `
+resource "gridscale_ip" "testip" {
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+  failover = true
+  reverse_dns = "test.example.com"
+}`
+
+func TestGridScaleIPRead_notFound(t *testing.T) {
+	status := 404
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	api_client := &Config{Endpoint: server.URL}
+
+	d := schema.TestResourceDataRaw(t, resourceGridScaleIP().Schema, map[string]interface{}{})
+	d.SetId("ip-uuid")
+	if err := resourceGridScaleIPRead(d, api_client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "" {
+		t.Fatal("expected a deleted IP to be removed from the state")
+	}
+
+	attachment := schema.TestResourceDataRaw(t, resourceGridScaleServerIPAttachment().Schema, map[string]interface{}{
+		"server_id": "server-uuid",
+		"ip_id":     "ip-uuid",
+	})
+	attachment.SetId(attachmentId("server-uuid", "ip-uuid"))
+	if err := resourceGridScaleServerIPAttachmentRead(attachment, api_client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if attachment.Id() != "" {
+		t.Fatal("expected the attachment of a deleted IP to be removed from the state")
+	}
+
+	status = 500
+	d.SetId("ip-uuid")
+	if err := resourceGridScaleIPRead(d, api_client); err == nil {
+		t.Fatal("expected other errors to be returned")
+	}
+	if d.Id() != "ip-uuid" {
+		t.Fatal("expected the IP to stay in the state on other errors")
+	}
+}
@@ -0,0 +1,133 @@
+package gridscale
+
+import (
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceGridScaleIsoImage() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGridScaleIsoImageCreate,
+		Read:   resourceGridScaleIsoImageRead,
+		Update: resourceGridScaleIsoImageUpdate,
+		Delete: resourceGridScaleIsoImageDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"location_uuid": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"source_url": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "HTTP(S) URL the image is downloaded from.",
+			},
+			"labels":                 labelsSchema(),
+			"default_labels_applied": defaultLabelsAppliedSchema(),
+			"label_map":              labelMapSchema(),
+			"capacity": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Size of the image in GB.",
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceGridScaleIsoImageCreate(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	isoImageId, err := api_client.createIsoImage(
+		d.Get("location_uuid").(string),
+		d.Get("name").(string),
+		d.Get("source_url").(string),
+		objectLabels(d, api_client),
+	)
+	if err != nil {
+		return err
+	}
+	d.SetId(isoImageId)
+
+	err = waitForStatus("ISO image "+isoImageId, "active", d.Timeout(schema.TimeoutCreate), func() (string, error) {
+		image, err := api_client.getIsoImage(isoImageId)
+		if err != nil {
+			return "", err
+		}
+		return image.Status, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return resourceGridScaleIsoImageRead(d, meta)
+}
+
+func resourceGridScaleIsoImageRead(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+
+	image, err := api_client.getIsoImage(d.Id())
+	if isNotFound(err) {
+		log.Printf("[WARN] ISO image %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("location_uuid", image.LocationID)
+	d.Set("name", image.Name)
+	d.Set("source_url", image.SourceURL)
+	if _, err := readObjectLabels(d, api_client, image.Labels); err != nil {
+		return err
+	}
+	d.Set("capacity", image.Capacity)
+	d.Set("status", image.Status)
+	return nil
+}
+
+func resourceGridScaleIsoImageUpdate(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+
+	if d.HasChange("name") {
+		err := api_client.updateIsoImage(d.Id(), patchIsoImageRequest{
+			Name: d.Get("name").(string),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := updateObjectLabels(d, api_client, "/objects/isoimages/"+d.Id()); err != nil {
+		return err
+	}
+
+	return resourceGridScaleIsoImageRead(d, meta)
+}
+
+func resourceGridScaleIsoImageDelete(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	err := api_client.deleteIsoImage(d.Id())
+
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+
+	return nil
+}
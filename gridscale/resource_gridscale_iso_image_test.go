@@ -0,0 +1,84 @@
+package gridscale
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGridScaleIsoImage_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDGridScaleIsoImageDestroyCheck,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckGridScaleIsoImageConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gridscale_iso_image.installer", "name", "installer"),
+					resource.TestCheckResourceAttr("gridscale_iso_image.installer", "status", "active"),
+					resource.TestCheckResourceAttrSet("gridscale_iso_image.installer", "capacity"),
+					testAccCheckGridScaleServerIsoImage("gridscale_server.installer", "gridscale_iso_image.installer"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDGridScaleIsoImageDestroyCheck(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Config)
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gridscale_iso_image" {
+			continue
+		}
+		_, err := client.getIsoImage(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("ISO image %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckGridScaleServerIsoImage(n string, image string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Config)
+
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("testAccCheckGridScaleServerIsoImage: Not found: %s", n)
+		}
+		img, ok := s.RootModule().Resources[image]
+		if !ok {
+			return fmt.Errorf("testAccCheckGridScaleServerIsoImage: Not found: %s", image)
+		}
+
+		relations, err := client.getServerRelations(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		for _, rel := range relations.IsoImages {
+			if rel.IsoImageID == img.Primary.ID {
+				return nil
+			}
+		}
+		return fmt.Errorf("ISO image %s is not mounted in server %s", img.Primary.ID, rs.Primary.ID)
+	}
+}
+
+const testAccCheckGridScaleIsoImageConfig_basic = `
+resource "gridscale_iso_image" "installer" {
+  name = "installer"
+  source_url = "http://tinycorelinux.net/9.x/x86/release/TinyCore-current.iso"
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+}
+
+resource "gridscale_server" "installer" {
+  name = "isoserver"
+  cores = 1
+  memory = 1
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+  iso_image_id = "${gridscale_iso_image.installer.id}"
+}`
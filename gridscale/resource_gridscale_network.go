@@ -1,6 +1,11 @@
 package gridscale
 
 import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
@@ -12,35 +17,48 @@ func resourceGridScaleNetwork() *schema.Resource {
 		Read:   resourceGridScaleNetworkRead,
 		Update: resourceGridScaleNetworkUpdate,
 		Delete: resourceGridScaleNetworkDelete,
-		Schema: map[string]*schema.Schema{
+		Timeouts: &schema.ResourceTimeout{
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: withMetadataSchema(map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
 			},
 			"location_uuid": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Defaults to the provider's default_location.",
 			},
 			"l2security": {
 				Type:     schema.TypeBool,
 				Optional: true,
 			},
-			"labels":{
-				Type: schema.TypeList,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-				Optional: true,
+			"force_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Disconnect the network from its servers when destroying it.",
 			},
-		},
+			"delete_protection": deleteProtectionSchema(),
+			"labels": labelsSchema(),
+			"default_labels_applied": defaultLabelsAppliedSchema(),
+			"label_map": labelMapSchema(),
+		}),
 	}
 }
 
 func resourceGridScaleNetworkCreate(d *schema.ResourceData, meta interface{}) error {
 	api_client := meta.(*Config)
+	if err := setResourceLocation(d, api_client); err != nil {
+		return err
+	}
 	network, err := api_client.CreateNetwork(
 		d.Get("location_uuid").(string),
 		d.Get("name").(string),
 		d.Get("l2security").(bool),
-		nil,
+		objectLabels(d, api_client),
 	)
 	if err != nil {
 		return err
@@ -53,20 +71,30 @@ func resourceGridScaleNetworkRead(d *schema.ResourceData, meta interface{}) erro
 	api_client := meta.(*Config)
 	networkId := d.Id()
 
-	network, err := api_client.GetNetwork(networkId)
+	network, err := api_client.readNetwork(networkId)
 	if err != nil {
 		return err
 	}
 
 	d.Set("name", network.Name)
+	d.Set("location_uuid", network.LocationID)
+	protected, err := readObjectLabels(d, api_client, network.Labels)
+	if err != nil {
+		return err
+	}
+	d.Set("delete_protection", protected)
+	setObjectMetadata(d, &network.objectMetadata)
 	return nil
 }
 
 func resourceGridScaleNetworkUpdate(d *schema.ResourceData, meta interface{}) error {
 	api_client := meta.(*Config)
 	networkId := d.Id()
+	api_client.batch.markStale(networkId)
 	updateNetworkName(d, api_client, networkId)
-	updateNetworkLabels(d, api_client, networkId)
+	if err := updateObjectLabels(d, api_client, "/objects/networks/"+networkId); err != nil {
+		return err
+	}
 
 	return resourceGridScaleNetworkRead(d, meta)
 }
@@ -74,11 +102,40 @@ func resourceGridScaleNetworkUpdate(d *schema.ResourceData, meta interface{}) er
 func resourceGridScaleNetworkDelete(d *schema.ResourceData, meta interface{}) error {
 	api_client := meta.(*Config)
 	networkId := d.Id()
-	err := api_client.DeleteNetwork(networkId)
+	timeout := d.Timeout(schema.TimeoutDelete)
 
+	network, err := api_client.getNetwork(networkId)
+	if isNotFound(err) {
+		log.Printf("[WARN] Network %s not found, removing from state", networkId)
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
 		return err
 	}
+	if err := checkDeleteProtection("Network "+networkId, network.Labels); err != nil {
+		return err
+	}
+	serverIds := []string{}
+	for _, rel := range network.Relations.Servers {
+		serverIds = append(serverIds, rel.ServerID)
+	}
+	if len(serverIds) > 0 {
+		if !d.Get("force_destroy").(bool) {
+			return fmt.Errorf("Network %s is still connected to servers %s. Set force_destroy to disconnect them",
+				networkId, strings.Join(serverIds, ", "))
+		}
+		err = detachFromServers(api_client, serverIds, timeout, func(serverId string) error {
+			return api_client.DisconnectNetwork(networkId, serverId)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := deleteObject(api_client, "/objects/networks/"+networkId, timeout); err != nil {
+		return err
+	}
 	d.SetId("")
 
 	return nil
@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
-	"github.com/parce-iot/gridscale"
+	"github.com/gridscale/terraform-provider-gridscale/internal/gridscale"
 )
 
 
@@ -1,7 +1,11 @@
 package gridscale
 
 import (
+	"log"
+	"time"
+
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/gridscale/terraform-provider-gridscale/internal/gridscale"
 )
 
 func resourceGridScaleServer() *schema.Resource {
@@ -10,11 +14,16 @@ func resourceGridScaleServer() *schema.Resource {
 		Read:   resourceGridScaleServerRead,
 		Update: resourceGridScaleServerUpdate,
 		Delete: resourceGridScaleServerDelete,
-		Schema: map[string]*schema.Schema{
+		Timeouts: &schema.ResourceTimeout{
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: withEstimatedCostSchema(withMetadataSchema(map[string]*schema.Schema{
 			//Server parameters
 			"location_uuid": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Defaults to the provider's default_location.",
 			},
 			"name": {
 				Type:     schema.TypeString,
@@ -32,19 +41,19 @@ func resourceGridScaleServer() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
-			"labels":{
-				Type: schema.TypeList,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-				Optional: true,
-			},
+			"labels": labelsSchema(),
+			"default_labels_applied": defaultLabelsAppliedSchema(),
+			"label_map": labelMapSchema(),
+			"delete_protection": deleteProtectionSchema(),
 			"power_on": {
 				Type:     schema.TypeBool,
 				Optional: true,
 			},
 
 			"ip_address": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:       schema.TypeString,
+				Optional:   true,
+				Deprecated: "Use the gridscale_server_ip_attachment resource to connect IP addresses.",
 			},
 			"storage_id": {
 				Type:     schema.TypeString,
@@ -62,37 +71,149 @@ func resourceGridScaleServer() *schema.Resource {
 				Type:     schema.TypeInt,
 				Optional: true,
 			},
-		},
+			"public_network": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Connect the server to the public network of its location.",
+			},
+			"auto_assign_ipv4": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Allocate an IPv4 address and connect it to the server. Requires public_network.",
+			},
+			"auto_assign_ipv6": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Allocate an IPv6 address and connect it to the server. Requires public_network.",
+			},
+			"ipv4_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ipv4_address": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ipv6_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ipv6_address": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"console_token": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Token for the server's VNC console.",
+			},
+		})),
 	}
 }
 
 func resourceGridScaleServerCreate(d *schema.ResourceData, meta interface{}) error {
 
 	api_client := meta.(*Config)
+	if err := validateServerPublicIPs(d); err != nil {
+		return err
+	}
+	if err := setResourceLocation(d, api_client); err != nil {
+		return err
+	}
+
 	server, err := api_client.CreateServer(
 		d.Get("location_uuid").(string),
 		d.Get("name").(string),
 		d.Get("cores").(int),
 		d.Get("memory").(int),
-		nil,
+		objectLabels(d, api_client),
 	)
 	if err != nil {
 		return err
 	}
 	d.SetId(server.ID)
+
+	if err := updateServerPublicNetwork(d, api_client, server.ID); err != nil {
+		return err
+	}
+	if err := updateServerStorage(d, api_client, server.ID); err != nil {
+		return err
+	}
+	if err := updateServerIsoImage(d, api_client, server.ID); err != nil {
+		return err
+	}
+	for _, family := range []int{4, 6} {
+		if err := updateServerPublicIP(d, api_client, server.ID, family); err != nil {
+			return err
+		}
+	}
+
 	return resourceGridScaleServerRead(d, meta)
 }
 
 func resourceGridScaleServerRead(d *schema.ResourceData, meta interface{}) error {
 	serverId := d.Id()
 	api_client := meta.(*Config)
-	server, err := api_client.GetServer(serverId)
+	server, relations, err := api_client.readServer(serverId)
 
 	if err != nil {
 		return err
 	}
 
 	d.Set("name", server.Name)
+	d.Set("location_uuid", server.LocationID)
+	protected, err := readObjectLabels(d, api_client, server.Labels)
+	if err != nil {
+		return err
+	}
+	d.Set("delete_protection", protected)
+	setObjectMetadata(d, serverMetadata(server))
+	d.Set("console_token", server.ConsoleToken)
+	// The client library does not decode the memory of a server, so the
+	// estimate uses the configured sizes.
+	readEstimatedCost(d, api_client, func(prices []gridscale.Price) (float64, error) {
+		return estimateServerCost(prices, d.Get("cores").(int), d.Get("memory").(int))
+	})
+
+	if relations == nil {
+		relations, err = api_client.getServerRelations(serverId)
+		if err != nil {
+			return err
+		}
+	}
+
+	publicNetwork := false
+	if len(relations.Networks) > 0 {
+		network, err := api_client.getPublicNetwork(server.LocationID)
+		if err != nil {
+			return err
+		}
+		publicNetwork = serverHasNetwork(relations, network.ID)
+	}
+	d.Set("public_network", publicNetwork)
+
+	// Only the storage managed through storage_id is tracked here, others may
+	// be attached with gridscale_server_storage_attachment.
+	if storageId := d.Get("storage_id").(string); storageId != "" {
+		d.Set("storage_id", "")
+		for _, rel := range relations.Storages {
+			if rel.StorageID == storageId {
+				d.Set("storage_id", rel.StorageID)
+				d.Set("bootdevice", rel.BootDevice)
+			}
+		}
+	}
+
+	isoImageId := ""
+	if len(relations.IsoImages) > 0 {
+		isoImageId = relations.IsoImages[0].IsoImageID
+	}
+	d.Set("iso_image_id", isoImageId)
+
+	for _, family := range []int{4, 6} {
+		readServerPublicIP(d, relations, family)
+	}
 
 	return nil
 }
@@ -100,14 +221,46 @@ func resourceGridScaleServerRead(d *schema.ResourceData, meta interface{}) error
 func resourceGridScaleServerUpdate(d *schema.ResourceData, meta interface{}) error {
 	api_client := meta.(*Config)
 	serverId := d.Id()
+	api_client.batch.markStale(serverId)
+
+	if err := validateServerPublicIPs(d); err != nil {
+		return err
+	}
 
 	updateServerName(d, api_client, serverId)
 	updateServerCores(d, api_client, serverId)
 	updateServerMemory(d, api_client, serverId)
+	if err := updateObjectLabels(d, api_client, "/objects/servers/"+serverId); err != nil {
+		return err
+	}
 	updateServerNetwork(d,api_client, serverId)
-	updateServerStorage(d,api_client, serverId)
+	if err := updateServerStorage(d, api_client, serverId); err != nil {
+		return err
+	}
 	//updateServerPower(d,api_client, serverId)
-	updateServerIsoImage(d,api_client, serverId)
+	if err := updateServerIsoImage(d, api_client, serverId); err != nil {
+		return err
+	}
+
+	// Release addresses before leaving the public network and join it
+	// before allocating new ones.
+	if !d.Get("public_network").(bool) {
+		for _, family := range []int{4, 6} {
+			if err := updateServerPublicIP(d, api_client, serverId, family); err != nil {
+				return err
+			}
+		}
+	}
+	if err := updateServerPublicNetwork(d, api_client, serverId); err != nil {
+		return err
+	}
+	if d.Get("public_network").(bool) {
+		for _, family := range []int{4, 6} {
+			if err := updateServerPublicIP(d, api_client, serverId, family); err != nil {
+				return err
+			}
+		}
+	}
 
 	return resourceGridScaleServerRead(d, meta)
 }
@@ -116,11 +269,63 @@ func resourceGridScaleServerUpdate(d *schema.ResourceData, meta interface{}) err
 func resourceGridScaleServerDelete(d *schema.ResourceData, meta interface{}) error {
 	api_client := meta.(*Config)
 	serverId := d.Id()
-	err := api_client.DeleteServer(serverId)
+	timeout := d.Timeout(schema.TimeoutDelete)
 
+	server, relations, err := api_client.getServer(serverId)
+	if isNotFound(err) {
+		log.Printf("[WARN] Server %s not found, removing from state", serverId)
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
 		return err
 	}
+	if err := checkDeleteProtection("Server "+serverId, server.Labels); err != nil {
+		return err
+	}
+	if server.Power {
+		log.Printf("[INFO] Powering off server %s before deleting it", serverId)
+		if err := setServerPower(api_client, serverId, false, timeout); err != nil {
+			return err
+		}
+	}
+
+	// Detach everything first, so the objects outlive the server and the
+	// API does not refuse the deletion.
+	for _, rel := range relations.IsoImages {
+		if err := api_client.DisconnectIsoImage(rel.IsoImageID, serverId); err != nil {
+			return err
+		}
+	}
+	for _, rel := range relations.PublicIPs {
+		if err := api_client.DisconnectIPAddress(rel.IPID, serverId); err != nil {
+			return err
+		}
+	}
+	for _, rel := range relations.Networks {
+		if err := api_client.DisconnectNetwork(rel.NetworkID, serverId); err != nil {
+			return err
+		}
+	}
+	for _, rel := range relations.Storages {
+		if err := api_client.DisconnectStorage(rel.StorageID, serverId); err != nil {
+			return err
+		}
+	}
+
+	if err := deleteObject(api_client, "/objects/servers/"+serverId, timeout); err != nil {
+		return err
+	}
+
+	// Addresses allocated through auto_assign_ipv4/6 belong to the server.
+	for _, key := range []string{"ipv4_id", "ipv6_id"} {
+		if ipId := d.Get(key).(string); ipId != "" {
+			err := api_client.apiCall("DELETE", "/objects/ips/"+ipId, nil, nil)
+			if err != nil && !isNotFound(err) {
+				return err
+			}
+		}
+	}
 	d.SetId("")
 
 	return nil
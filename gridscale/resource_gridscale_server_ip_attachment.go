@@ -0,0 +1,160 @@
+package gridscale
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceGridScaleServerIPAttachment() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGridScaleServerIPAttachmentCreate,
+		Read:   resourceGridScaleServerIPAttachmentRead,
+		Update: resourceGridScaleServerIPAttachmentUpdate,
+		Delete: resourceGridScaleServerIPAttachmentDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGridScaleServerIPAttachmentImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Changing the server moves the IP without recreating the attachment.",
+			},
+			"ip_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceGridScaleServerIPAttachmentCreate(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	serverId := d.Get("server_id").(string)
+	ipId := d.Get("ip_id").(string)
+
+	if err := connectServerIP(api_client, ipId, serverId); err != nil {
+		return err
+	}
+	d.SetId(attachmentId(serverId, ipId))
+	return resourceGridScaleServerIPAttachmentRead(d, meta)
+}
+
+func resourceGridScaleServerIPAttachmentRead(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	serverId := d.Get("server_id").(string)
+	ipId := d.Get("ip_id").(string)
+
+	ip, err := api_client.getIP(ipId)
+	if isNotFound(err) {
+		log.Printf("[WARN] IP %s not found, removing attachment from state", ipId)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, rel := range ip.Relations.Servers {
+		if rel.ServerID == serverId {
+			return nil
+		}
+	}
+
+	log.Printf("[WARN] IP %s is no longer connected to server %s, removing attachment from state", ipId, serverId)
+	d.SetId("")
+	return nil
+}
+
+func resourceGridScaleServerIPAttachmentUpdate(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	ipId := d.Get("ip_id").(string)
+
+	if d.HasChange("server_id") {
+		oldServer, newServer := d.GetChange("server_id")
+		if err := disconnectServerIP(api_client, ipId, oldServer.(string)); err != nil {
+			return err
+		}
+		if err := connectServerIP(api_client, ipId, newServer.(string)); err != nil {
+			return err
+		}
+		d.SetId(attachmentId(newServer.(string), ipId))
+	}
+
+	return resourceGridScaleServerIPAttachmentRead(d, meta)
+}
+
+func resourceGridScaleServerIPAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+
+	err := disconnectServerIP(api_client, d.Get("ip_id").(string), d.Get("server_id").(string))
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+
+	return nil
+}
+
+func resourceGridScaleServerIPAttachmentImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	serverId, ipId, err := parseAttachmentId(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.Set("server_id", serverId)
+	d.Set("ip_id", ipId)
+	return []*schema.ResourceData{d}, nil
+}
+
+// connectServerIP connects an IP to a server. When the API refuses, the IP
+// is inspected to explain why: it is already connected (409) or the server
+// and IP are in different locations (403).
+func connectServerIP(api_client *Config, ipId, serverId string) error {
+	err := api_client.ConnectIPAddress(ipId, serverId)
+	if err == nil {
+		return nil
+	}
+
+	ip, ipErr := api_client.getIP(ipId)
+	if ipErr != nil {
+		return err
+	}
+	for _, rel := range ip.Relations.Servers {
+		if rel.ServerID == serverId {
+			return fmt.Errorf("IP %s is already connected to server %s, import the attachment with the ID %s instead: %s",
+				ipId, serverId, attachmentId(serverId, ipId), err)
+		}
+		return fmt.Errorf("IP %s is already connected to server %s (%s), remove that attachment first: %s",
+			ipId, rel.ServerName, rel.ServerID, err)
+	}
+
+	server, serverErr := api_client.GetServer(serverId)
+	if serverErr == nil && server.LocationID != ip.LocationID {
+		return fmt.Errorf("IP %s is in location %s but server %s is in location %s, they must be in the same location: %s",
+			ipId, ip.LocationID, serverId, server.LocationID, err)
+	}
+	return err
+}
+
+// disconnectServerIP disconnects an IP from a server. An IP that is not
+// connected to the server (any more) is not an error.
+func disconnectServerIP(api_client *Config, ipId, serverId string) error {
+	err := api_client.DisconnectIPAddress(ipId, serverId)
+	if err == nil {
+		return nil
+	}
+
+	ip, ipErr := api_client.getIP(ipId)
+	if ipErr != nil {
+		return err
+	}
+	for _, rel := range ip.Relations.Servers {
+		if rel.ServerID == serverId {
+			return err
+		}
+	}
+	return nil
+}
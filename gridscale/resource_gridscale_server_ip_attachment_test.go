@@ -0,0 +1,85 @@
+package gridscale
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGridScaleServerIPAttachment_Move(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckGridScaleServerIPAttachmentConfig("first"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGridScaleServerIPAttached("gridscale_server_ip_attachment.failover", "gridscale_server.first"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckGridScaleServerIPAttachmentConfig("second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGridScaleServerIPAttached("gridscale_server_ip_attachment.failover", "gridscale_server.second"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckGridScaleServerIPAttached(n, server string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Config)
+
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("testAccCheckGridScaleServerIPAttached: Not found: %s", n)
+		}
+		srv, ok := s.RootModule().Resources[server]
+		if !ok {
+			return fmt.Errorf("testAccCheckGridScaleServerIPAttached: Not found: %s", server)
+		}
+
+		ip, err := client.GetIP(rs.Primary.Attributes["ip_id"])
+		if err != nil {
+			return err
+		}
+		for _, rel := range ip.Servers {
+			if rel.ServerID == srv.Primary.ID {
+				return nil
+			}
+		}
+		return fmt.Errorf("IP %s is not connected to server %s", ip.ID, srv.Primary.ID)
+	}
+}
+
+func testAccCheckGridScaleServerIPAttachmentConfig(server string) string {
+	return fmt.Sprintf(`
+resource "gridscale_server" "first" {
+  name = "ipattachment-first"
+  cores = 1
+  memory = 1
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+  public_network = true
+}
+
+resource "gridscale_server" "second" {
+  name = "ipattachment-second"
+  cores = 1
+  memory = 1
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+  public_network = true
+}
+
+resource "gridscale_ip" "failover" {
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+  failover = true
+}
+
+resource "gridscale_server_ip_attachment" "failover" {
+  server_id = "${gridscale_server.%s.id}"
+  ip_id = "${gridscale_ip.failover.id}"
+}`, server)
+}
@@ -0,0 +1,138 @@
+package gridscale
+
+import (
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceGridScaleServerNetworkAttachment() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGridScaleServerNetworkAttachmentCreate,
+		Read:   resourceGridScaleServerNetworkAttachmentRead,
+		Update: resourceGridScaleServerNetworkAttachmentUpdate,
+		Delete: resourceGridScaleServerNetworkAttachmentDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGridScaleServerNetworkAttachmentImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"network_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"ordering": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"mac": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceGridScaleServerNetworkAttachmentCreate(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	serverId := d.Get("server_id").(string)
+	networkId := d.Get("network_id").(string)
+
+	err := api_client.ConnectNetwork(networkId, d.Get("ordering").(int), serverId)
+	if err != nil {
+		return err
+	}
+	d.SetId(attachmentId(serverId, networkId))
+	return resourceGridScaleServerNetworkAttachmentRead(d, meta)
+}
+
+func resourceGridScaleServerNetworkAttachmentRead(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	serverId := d.Get("server_id").(string)
+	networkId := d.Get("network_id").(string)
+
+	relations, err := api_client.getServerRelations(serverId)
+	if isNotFound(err) {
+		log.Printf("[WARN] Server %s not found, removing network attachment from state", serverId)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, rel := range relations.Networks {
+		if rel.NetworkID == networkId {
+			d.Set("ordering", rel.Ordering)
+			d.Set("mac", rel.MAC)
+			return nil
+		}
+	}
+
+	log.Printf("[WARN] Network %s is no longer connected to server %s, removing attachment from state", networkId, serverId)
+	d.SetId("")
+	return nil
+}
+
+func resourceGridScaleServerNetworkAttachmentUpdate(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+
+	if d.HasChange("ordering") {
+		err := api_client.updateServerNetworkRelation(
+			d.Get("server_id").(string),
+			d.Get("network_id").(string),
+			d.Get("ordering").(int),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceGridScaleServerNetworkAttachmentRead(d, meta)
+}
+
+func resourceGridScaleServerNetworkAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	serverId := d.Get("server_id").(string)
+	networkId := d.Get("network_id").(string)
+
+	err := api_client.DisconnectNetwork(networkId, serverId)
+	if err != nil {
+		// Nothing to do if the network is already gone from the server.
+		relations, relErr := api_client.getServerRelations(serverId)
+		if relErr == nil && !serverHasNetwork(relations, networkId) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+
+	return nil
+}
+
+func resourceGridScaleServerNetworkAttachmentImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	serverId, networkId, err := parseAttachmentId(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.Set("server_id", serverId)
+	d.Set("network_id", networkId)
+	return []*schema.ResourceData{d}, nil
+}
+
+func serverHasNetwork(relations *serverRelations, networkId string) bool {
+	for _, rel := range relations.Networks {
+		if rel.NetworkID == networkId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package gridscale
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccGridScaleServerNetworkAttachment_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckGridScaleServerNetworkAttachmentConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gridscale_server_network_attachment.attached", "ordering", "1"),
+					resource.TestCheckResourceAttrSet("gridscale_server_network_attachment.attached", "mac"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "gridscale_server_network_attachment.attached",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+const testAccCheckGridScaleServerNetworkAttachmentConfig_basic = `
+resource "gridscale_server" "attached" {
+  name = "attachment-server"
+  cores = 1
+  memory = 1
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+}
+
+resource "gridscale_network" "attached" {
+  name = "attachment-network"
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+}
+
+resource "gridscale_server_network_attachment" "attached" {
+  server_id = "${gridscale_server.attached.id}"
+  network_id = "${gridscale_network.attached.id}"
+  ordering = 1
+}
+`
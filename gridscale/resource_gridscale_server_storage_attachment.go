@@ -0,0 +1,147 @@
+package gridscale
+
+import (
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceGridScaleServerStorageAttachment() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGridScaleServerStorageAttachmentCreate,
+		Read:   resourceGridScaleServerStorageAttachmentRead,
+		Update: resourceGridScaleServerStorageAttachmentUpdate,
+		Delete: resourceGridScaleServerStorageAttachmentDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGridScaleServerStorageAttachmentImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"storage_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"bootdevice": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"lun": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"bus": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"controller": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceGridScaleServerStorageAttachmentCreate(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	serverId := d.Get("server_id").(string)
+	storageId := d.Get("storage_id").(string)
+
+	err := api_client.ConnectStorage(storageId, d.Get("bootdevice").(bool), serverId)
+	if err != nil {
+		return err
+	}
+	d.SetId(attachmentId(serverId, storageId))
+	return resourceGridScaleServerStorageAttachmentRead(d, meta)
+}
+
+func resourceGridScaleServerStorageAttachmentRead(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	serverId := d.Get("server_id").(string)
+	storageId := d.Get("storage_id").(string)
+
+	relations, err := api_client.getServerRelations(serverId)
+	if isNotFound(err) {
+		log.Printf("[WARN] Server %s not found, removing storage attachment from state", serverId)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, rel := range relations.Storages {
+		if rel.StorageID == storageId {
+			d.Set("bootdevice", rel.BootDevice)
+			d.Set("lun", rel.LUN)
+			d.Set("bus", rel.Bus)
+			d.Set("controller", rel.Controller)
+			return nil
+		}
+	}
+
+	log.Printf("[WARN] Storage %s is no longer connected to server %s, removing attachment from state", storageId, serverId)
+	d.SetId("")
+	return nil
+}
+
+func resourceGridScaleServerStorageAttachmentUpdate(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+
+	if d.HasChange("bootdevice") {
+		err := api_client.updateServerStorageRelation(
+			d.Get("server_id").(string),
+			d.Get("storage_id").(string),
+			d.Get("bootdevice").(bool),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceGridScaleServerStorageAttachmentRead(d, meta)
+}
+
+func resourceGridScaleServerStorageAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	serverId := d.Get("server_id").(string)
+	storageId := d.Get("storage_id").(string)
+
+	err := api_client.DisconnectStorage(storageId, serverId)
+	if err != nil {
+		// Nothing to do if the storage is already gone from the server.
+		relations, relErr := api_client.getServerRelations(serverId)
+		if relErr == nil && !serverHasStorage(relations, storageId) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+
+	return nil
+}
+
+func resourceGridScaleServerStorageAttachmentImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	serverId, storageId, err := parseAttachmentId(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.Set("server_id", serverId)
+	d.Set("storage_id", storageId)
+	return []*schema.ResourceData{d}, nil
+}
+
+func serverHasStorage(relations *serverRelations, storageId string) bool {
+	for _, rel := range relations.Storages {
+		if rel.StorageID == storageId {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package gridscale
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccGridScaleServerStorageAttachment_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckGridScaleServerStorageAttachmentConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gridscale_server_storage_attachment.attached", "bootdevice", "true"),
+					resource.TestCheckResourceAttrSet("gridscale_server_storage_attachment.attached", "lun"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "gridscale_server_storage_attachment.attached",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+const testAccCheckGridScaleServerStorageAttachmentConfig_basic = `
+resource "gridscale_server" "attached" {
+  name = "attachment-server"
+  cores = 1
+  memory = 1
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+}
+
+resource "gridscale_storage" "attached" {
+  name = "attachment-storage"
+  capacity = 1
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+}
+
+resource "gridscale_server_storage_attachment" "attached" {
+  server_id = "${gridscale_server.attached.id}"
+  storage_id = "${gridscale_storage.attached.id}"
+  bootdevice = true
+}
+`
@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
-	"github.com/parce-iot/gridscale"
+	"github.com/gridscale/terraform-provider-gridscale/internal/gridscale"
 )
 
 
@@ -45,6 +45,58 @@ func TestAccGridScaleServer_Basic(t *testing.T) {
 	})
 }
 
+func TestAccGridScaleServer_StorageSurvivesRename(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {testAccPreCheck(t)},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDGridScaleServerDestroyCheck,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckGridScaleServerConfig_storage("storageserver"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGridScaleServerStorageConnected("gridscale_server.testserver", "gridscale_storage.serverstorage"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckGridScaleServerConfig_storage("renamedstorageserver"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gridscale_server.testserver", "name", "renamedstorageserver"),
+					testAccCheckGridScaleServerStorageConnected("gridscale_server.testserver", "gridscale_storage.serverstorage"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccGridScaleServer_PublicNetwork(t *testing.T) {
+	var server gridscale.Server
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {testAccPreCheck(t)},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDGridScaleServerDestroyCheck,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckGridScaleServerConfig_publicNetwork,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGridScaleServerExists("gridscale_server.testserver", &server),
+					resource.TestCheckResourceAttr("gridscale_server.testserver", "public_network", "true"),
+					resource.TestCheckResourceAttrSet("gridscale_server.testserver", "ipv4_id"),
+					resource.TestCheckResourceAttrSet("gridscale_server.testserver", "ipv4_address"),
+					resource.TestCheckResourceAttr("gridscale_server.testserver", "ipv6_id", ""),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckGridScaleServerConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gridscale_server.testserver", "public_network", "false"),
+					resource.TestCheckResourceAttr("gridscale_server.testserver", "ipv4_id", ""),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckDGridScaleServerDestroyCheck(s *terraform.State) error {
 	client := testAccProvider.Meta().(*Config)
 	for _, rs := range s.RootModule().Resources {
@@ -75,6 +127,30 @@ func testAccCheckGridScaleServerAttributes(n string, name string) resource.TestC
 	}
 }
 
+func testAccCheckGridScaleServerStorageConnected(n string, storage string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*Config)
+
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("testAccCheckGridScaleServerStorageConnected: Not found: %s", n)
+		}
+		st, ok := s.RootModule().Resources[storage]
+		if !ok {
+			return fmt.Errorf("testAccCheckGridScaleServerStorageConnected: Not found: %s", storage)
+		}
+
+		relations, err := client.getServerRelations(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if !serverHasStorage(relations, st.Primary.ID) {
+			return fmt.Errorf("Storage %s is not connected to server %s", st.Primary.ID, rs.Primary.ID)
+		}
+		return nil
+	}
+}
+
 func testAccCheckGridScaleServerExists(n string, server *gridscale.Server) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		client := testAccProvider.Meta().(*Config)
@@ -135,3 +211,32 @@ resource "gridscale_server" "testserver" {
   network_id = "${gridscale_network.servernetwork.id}"
   ordering = 1
 }`
+
+const testAccCheckGridScaleServerConfig_publicNetwork = `
+resource "gridscale_server" "testserver" {
+  name = "testserver"
+  cores = 1
+  memory = 1
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+  public_network = true
+  auto_assign_ipv4 = true
+}
+`
+
+func testAccCheckGridScaleServerConfig_storage(name string) string {
+	return fmt.Sprintf(`
+resource "gridscale_storage" "serverstorage" {
+  name = "serverstorage"
+  capacity = "1"
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+}
+
+resource "gridscale_server" "testserver" {
+  name = "%s"
+  cores = 1
+  memory = 1
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+  storage_id = "${gridscale_storage.serverstorage.id}"
+  bootdevice = true
+}`, name)
+}
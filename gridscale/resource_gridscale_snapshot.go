@@ -0,0 +1,139 @@
+package gridscale
+
+import (
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceGridScaleSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGridScaleSnapshotCreate,
+		Read:   resourceGridScaleSnapshotRead,
+		Update: resourceGridScaleSnapshotUpdate,
+		Delete: resourceGridScaleSnapshotDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGridScaleSnapshotImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"storage_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"labels":                 labelsSchema(),
+			"default_labels_applied": defaultLabelsAppliedSchema(),
+			"label_map":              labelMapSchema(),
+			"capacity": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"create_time": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceGridScaleSnapshotCreate(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	storageId := d.Get("storage_id").(string)
+
+	snapshotId, err := api_client.createSnapshot(
+		storageId,
+		d.Get("name").(string),
+		objectLabels(d, api_client),
+	)
+	if err != nil {
+		return err
+	}
+	d.SetId(snapshotId)
+
+	err = waitForStatus("snapshot "+snapshotId, "active", d.Timeout(schema.TimeoutCreate), func() (string, error) {
+		snapshot, err := api_client.getSnapshot(storageId, snapshotId)
+		if err != nil {
+			return "", err
+		}
+		return snapshot.Status, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return resourceGridScaleSnapshotRead(d, meta)
+}
+
+func resourceGridScaleSnapshotRead(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+
+	snapshot, err := api_client.getSnapshot(d.Get("storage_id").(string), d.Id())
+	if isNotFound(err) {
+		log.Printf("[WARN] Snapshot %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("name", snapshot.Name)
+	if _, err := readObjectLabels(d, api_client, snapshot.Labels); err != nil {
+		return err
+	}
+	d.Set("capacity", snapshot.Capacity)
+	d.Set("status", snapshot.Status)
+	d.Set("create_time", snapshot.CreateTime.Format(time.RFC3339))
+	return nil
+}
+
+func resourceGridScaleSnapshotUpdate(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+
+	storageId := d.Get("storage_id").(string)
+	if d.HasChange("name") {
+		err := api_client.updateSnapshot(storageId, d.Id(), d.Get("name").(string), nil)
+		if err != nil {
+			return err
+		}
+	}
+	if err := updateObjectLabels(d, api_client, snapshotPath(storageId)+"/"+d.Id()); err != nil {
+		return err
+	}
+
+	return resourceGridScaleSnapshotRead(d, meta)
+}
+
+func resourceGridScaleSnapshotDelete(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	err := api_client.deleteSnapshot(d.Get("storage_id").(string), d.Id())
+
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+
+	return nil
+}
+
+func resourceGridScaleSnapshotImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	storageId, snapshotId, err := splitCompositeId(d.Id(), "storage_id/snapshot_id")
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(snapshotId)
+	d.Set("storage_id", storageId)
+	return []*schema.ResourceData{d}, nil
+}
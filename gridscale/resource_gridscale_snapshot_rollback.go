@@ -0,0 +1,143 @@
+package gridscale
+
+import (
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceGridScaleSnapshotRollback() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGridScaleSnapshotRollbackCreate,
+		Read:   resourceGridScaleSnapshotRollbackRead,
+		Delete: resourceGridScaleSnapshotRollbackDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"snapshot_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"storage_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Storage the snapshot belongs to. Looked up from the snapshot if not set.",
+			},
+			"trigger": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Changing this value performs the rollback again.",
+			},
+			"rollback_time": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"capacity": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Capacity of the storage after the rollback.",
+			},
+		},
+	}
+}
+
+// resourceGridScaleSnapshotRollbackCreate rolls the storage back to the
+// snapshot. Servers using the storage are powered off during the rollback
+// and powered on again afterwards if they were running, also if the rollback
+// fails.
+func resourceGridScaleSnapshotRollbackCreate(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	snapshotId := d.Get("snapshot_id").(string)
+	timeout := d.Timeout(schema.TimeoutCreate)
+
+	storageId := d.Get("storage_id").(string)
+	if storageId == "" {
+		var err error
+		storageId, err = api_client.findSnapshotStorage(snapshotId)
+		if err != nil {
+			return err
+		}
+	}
+
+	storage, err := api_client.GetStorage(storageId)
+	if err != nil {
+		return err
+	}
+
+	serverIds := []string{}
+	for _, rel := range storage.Relations {
+		serverIds = append(serverIds, rel.ServerID)
+	}
+	log.Printf("[INFO] Powering off the servers of storage %s for the rollback", storageId)
+	running, err := powerOffServers(api_client, serverIds, timeout)
+	if err == nil {
+		err = rollbackStorage(d, api_client, storageId, snapshotId, timeout)
+	}
+	if powerErr := powerOnServers(api_client, running, timeout); err == nil {
+		err = powerErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return resourceGridScaleSnapshotRollbackRead(d, meta)
+}
+
+// rollbackStorage rolls the storage back and waits until it is active again.
+func rollbackStorage(d *schema.ResourceData, api_client *Config, storageId, snapshotId string, timeout time.Duration) error {
+	api_client.batch.markStale(storageId)
+	if err := api_client.rollbackSnapshot(storageId, snapshotId); err != nil {
+		return err
+	}
+	d.SetId(snapshotId + "-" + time.Now().UTC().Format("20060102150405"))
+	d.Set("storage_id", storageId)
+	d.Set("rollback_time", time.Now().UTC().Format(time.RFC3339))
+
+	return waitForStatus("storage "+storageId, "active", timeout, func() (string, error) {
+		storage, err := api_client.GetStorage(storageId)
+		if err != nil {
+			return "", err
+		}
+		return storage.Status, nil
+	})
+}
+
+func resourceGridScaleSnapshotRollbackRead(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	storageId := d.Get("storage_id").(string)
+
+	// The rollback is a one-off action. It stays in the state as long as the
+	// storage exists, so the next refresh picks up the rolled back storage.
+	// Only a storage that is gone removes it, any other error must not lead
+	// to a second rollback.
+	wrpr := struct {
+		Storage struct {
+			Capacity int `json:"capacity"`
+		} `json:"storage"`
+	}{}
+	err := api_client.apiCall("GET", "/objects/storages/"+storageId, nil, &wrpr)
+	if isNotFound(err) {
+		log.Printf("[WARN] Storage %s of rollback %s not found, removing from state", storageId, d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("capacity", wrpr.Storage.Capacity)
+	return nil
+}
+
+// resourceGridScaleSnapshotRollbackDelete only removes the rollback from the
+// state, a rollback cannot be undone.
+func resourceGridScaleSnapshotRollbackDelete(d *schema.ResourceData, meta interface{}) error {
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,59 @@
+package gridscale
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccGridScaleSnapshotRollback_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckGridScaleSnapshotRollbackConfig("first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("gridscale_snapshot_rollback.restore", "rollback_time"),
+					resource.TestCheckResourceAttrSet("gridscale_snapshot_rollback.restore", "storage_id"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckGridScaleSnapshotRollbackConfig("second"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gridscale_snapshot_rollback.restore", "trigger", "second"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckGridScaleSnapshotRollbackConfig(trigger string) string {
+	return fmt.Sprintf(`
+resource "gridscale_storage" "rollback" {
+  name = "rollback"
+  capacity = 1
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+}
+
+resource "gridscale_server" "rollback" {
+  name = "rollback"
+  cores = 1
+  memory = 1
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+  storage_id = "${gridscale_storage.rollback.id}"
+  bootdevice = true
+  power_on = true
+}
+
+resource "gridscale_snapshot" "known_good" {
+  storage_id = "${gridscale_storage.rollback.id}"
+  name = "known-good"
+}
+
+resource "gridscale_snapshot_rollback" "restore" {
+  snapshot_id = "${gridscale_snapshot.known_good.id}"
+  trigger = "%s"
+}`, trigger)
+}
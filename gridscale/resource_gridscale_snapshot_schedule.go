@@ -0,0 +1,208 @@
+package gridscale
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceGridScaleSnapshotSchedule() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGridScaleSnapshotScheduleCreate,
+		Read:   resourceGridScaleSnapshotScheduleRead,
+		Update: resourceGridScaleSnapshotScheduleUpdate,
+		Delete: resourceGridScaleSnapshotScheduleDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceGridScaleSnapshotScheduleImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"storage_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name_prefix": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the schedule, used as prefix for the names of its snapshots.",
+			},
+			"run_interval": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validateMinimum(60),
+				Description:  "Minutes between two snapshots.",
+			},
+			"keep_snapshots": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validateMinimum(1),
+				Description:  "Number of snapshots to retain, older ones are deleted.",
+			},
+			"next_runtime": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateRFC3339,
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return nextRuntimeUnchanged(old, new, time.Now())
+				},
+				Description: "Time of the next snapshot in RFC 3339 format. Times in the past are ignored.",
+			},
+			"labels":                 labelsSchema(),
+			"default_labels_applied": defaultLabelsAppliedSchema(),
+			"label_map":              labelMapSchema(),
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"snapshots": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id":          {Type: schema.TypeString, Computed: true},
+						"name":        {Type: schema.TypeString, Computed: true},
+						"create_time": {Type: schema.TypeString, Computed: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func validateMinimum(min int) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) (ws []string, errors []error) {
+		if v.(int) < min {
+			errors = append(errors, fmt.Errorf("%q must be at least %d, got %d", k, min, v.(int)))
+		}
+		return
+	}
+}
+
+func validateRFC3339(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := time.Parse(time.RFC3339, v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a RFC 3339 timestamp: %s", k, err))
+	}
+	return
+}
+
+// nextRuntimeUnchanged reports whether a configured next_runtime needs no
+// update. The API returns the time in UTC, and the schedule moves it forward
+// after every run, so the configured time only matters while it is in the
+// future and differs from the current one.
+func nextRuntimeUnchanged(old, new string, now time.Time) bool {
+	newTime, err := time.Parse(time.RFC3339, new)
+	if err != nil {
+		return false
+	}
+	if !newTime.After(now) {
+		return true
+	}
+	oldTime, err := time.Parse(time.RFC3339, old)
+	return err == nil && oldTime.Equal(newTime)
+}
+
+func snapshotScheduleRequestFromData(d *schema.ResourceData, api_client *Config) snapshotScheduleRequest {
+	req := snapshotScheduleRequest{
+		Name:          d.Get("name_prefix").(string),
+		Labels:        objectLabels(d, api_client),
+		RunInterval:   d.Get("run_interval").(int),
+		KeepSnapshots: d.Get("keep_snapshots").(int),
+	}
+	if v, ok := d.GetOk("next_runtime"); ok {
+		// Already checked by validateRFC3339.
+		next, _ := time.Parse(time.RFC3339, v.(string))
+		if next.After(time.Now()) {
+			req.NextRuntime = &next
+		}
+	}
+	return req
+}
+
+func resourceGridScaleSnapshotScheduleCreate(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+
+	scheduleId, err := api_client.createSnapshotSchedule(d.Get("storage_id").(string), snapshotScheduleRequestFromData(d, api_client))
+	if err != nil {
+		return err
+	}
+	d.SetId(scheduleId)
+	return resourceGridScaleSnapshotScheduleRead(d, meta)
+}
+
+func resourceGridScaleSnapshotScheduleRead(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+
+	schedule, err := api_client.getSnapshotSchedule(d.Get("storage_id").(string), d.Id())
+	if isNotFound(err) {
+		log.Printf("[WARN] Snapshot schedule %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("name_prefix", schedule.Name)
+	if _, err := readObjectLabels(d, api_client, schedule.Labels); err != nil {
+		return err
+	}
+	d.Set("run_interval", schedule.RunInterval)
+	d.Set("keep_snapshots", schedule.KeepSnapshots)
+	d.Set("next_runtime", schedule.NextRuntime.UTC().Format(time.RFC3339))
+	d.Set("status", schedule.Status)
+
+	snapshots := make([]map[string]interface{}, 0, len(schedule.Relations.Snapshots))
+	for _, snapshot := range schedule.Relations.Snapshots {
+		snapshots = append(snapshots, map[string]interface{}{
+			"id":          snapshot.ID,
+			"name":        snapshot.Name,
+			"create_time": snapshot.CreateTime.Format(time.RFC3339),
+		})
+	}
+	return d.Set("snapshots", snapshots)
+}
+
+func resourceGridScaleSnapshotScheduleUpdate(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+
+	storageId := d.Get("storage_id").(string)
+	req := snapshotScheduleRequestFromData(d, api_client)
+	req.Labels = nil
+	if !d.HasChange("next_runtime") {
+		req.NextRuntime = nil
+	}
+	err := api_client.updateSnapshotSchedule(storageId, d.Id(), req)
+	if err != nil {
+		return err
+	}
+	if err := updateObjectLabels(d, api_client, snapshotSchedulePath(storageId)+"/"+d.Id()); err != nil {
+		return err
+	}
+
+	return resourceGridScaleSnapshotScheduleRead(d, meta)
+}
+
+func resourceGridScaleSnapshotScheduleDelete(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	err := api_client.deleteSnapshotSchedule(d.Get("storage_id").(string), d.Id())
+
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+
+	return nil
+}
+
+func resourceGridScaleSnapshotScheduleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	storageId, scheduleId, err := splitCompositeId(d.Id(), "storage_id/schedule_id")
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(scheduleId)
+	d.Set("storage_id", storageId)
+	return []*schema.ResourceData{d}, nil
+}
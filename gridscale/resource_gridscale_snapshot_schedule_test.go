@@ -0,0 +1,89 @@
+package gridscale
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGridScaleSnapshotSchedule_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDGridScaleSnapshotScheduleDestroyCheck,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckGridScaleSnapshotScheduleConfig(60, 3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gridscale_snapshot_schedule.hourly", "name_prefix", "db-hourly"),
+					resource.TestCheckResourceAttr("gridscale_snapshot_schedule.hourly", "run_interval", "60"),
+					resource.TestCheckResourceAttr("gridscale_snapshot_schedule.hourly", "keep_snapshots", "3"),
+					resource.TestCheckResourceAttrSet("gridscale_snapshot_schedule.hourly", "next_runtime"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckGridScaleSnapshotScheduleConfig(120, 5),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gridscale_snapshot_schedule.hourly", "run_interval", "120"),
+					resource.TestCheckResourceAttr("gridscale_snapshot_schedule.hourly", "keep_snapshots", "5"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDGridScaleSnapshotScheduleDestroyCheck(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Config)
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gridscale_snapshot_schedule" {
+			continue
+		}
+		_, err := client.getSnapshotSchedule(rs.Primary.Attributes["storage_id"], rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("Snapshot schedule %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckGridScaleSnapshotScheduleConfig(interval, keep int) string {
+	return fmt.Sprintf(`
+resource "gridscale_storage" "database" {
+  name = "database"
+  capacity = 1
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+}
+
+resource "gridscale_snapshot_schedule" "hourly" {
+  storage_id = "${gridscale_storage.database.id}"
+  name_prefix = "db-hourly"
+  run_interval = %d
+  keep_snapshots = %d
+}`, interval, keep)
+}
+
+func TestNextRuntimeUnchanged(t *testing.T) {
+	now := time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		old, new string
+		expected bool
+	}{
+		{"2018-06-02T10:00:00Z", "2018-06-02T10:00:00Z", true},
+		// Same instant with a different offset.
+		{"2018-06-02T10:00:00Z", "2018-06-02T12:00:00+02:00", true},
+		{"2018-06-02T10:00:00Z", "2018-06-03T10:00:00Z", false},
+		// The schedule has run since, the configured time is in the past.
+		{"2018-06-02T10:00:00Z", "2018-05-30T10:00:00Z", true},
+		{"", "2018-06-02T10:00:00Z", false},
+		{"2018-06-02T10:00:00Z", "not a time", false},
+	}
+	for _, tc := range cases {
+		if got := nextRuntimeUnchanged(tc.old, tc.new, now); got != tc.expected {
+			t.Errorf("nextRuntimeUnchanged(%q, %q) = %t, expected %t", tc.old, tc.new, got, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package gridscale
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGridScaleSnapshot_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDGridScaleSnapshotDestroyCheck,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckGridScaleSnapshotConfig("before-deploy"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gridscale_snapshot.backup", "name", "before-deploy"),
+					resource.TestCheckResourceAttr("gridscale_snapshot.backup", "status", "active"),
+					resource.TestCheckResourceAttrSet("gridscale_snapshot.backup", "create_time"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccCheckGridScaleSnapshotConfig("renamed"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gridscale_snapshot.backup", "name", "renamed"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDGridScaleSnapshotDestroyCheck(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Config)
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gridscale_snapshot" {
+			continue
+		}
+		_, err := client.getSnapshot(rs.Primary.Attributes["storage_id"], rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("Snapshot %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckGridScaleSnapshotConfig(name string) string {
+	return fmt.Sprintf(`
+resource "gridscale_storage" "snapshotted" {
+  name = "snapshotted"
+  capacity = 1
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+}
+
+resource "gridscale_snapshot" "backup" {
+  storage_id = "${gridscale_storage.snapshotted.id}"
+  name = "%s"
+}`, name)
+}
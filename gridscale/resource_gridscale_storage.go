@@ -1,7 +1,12 @@
 package gridscale
 
 import (
+	"fmt"
+	"log"
+	"time"
+
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/gridscale/terraform-provider-gridscale/internal/gridscale"
 )
 
 
@@ -12,10 +17,17 @@ func resourceGridScaleStorage() *schema.Resource {
 		Read:   resourceGridScaleStorageRead,
 		Update: resourceGridScaleStorageUpdate,
 		Delete: resourceGridScaleStorageDelete,
-		Schema: map[string]*schema.Schema{
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(15 * time.Minute),
+			Update: schema.DefaultTimeout(15 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: withEstimatedCostSchema(withMetadataSchema(map[string]*schema.Schema{
 			"location_uuid": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Defaults to the provider's default_location.",
 			},
 			"name": {
 				Type:     schema.TypeString,
@@ -24,24 +36,91 @@ func resourceGridScaleStorage() *schema.Resource {
 			"capacity": {
 				Type:     schema.TypeInt,
 				Optional: true,
+				Computed: true,
+			},
+			"delete_protection": deleteProtectionSchema(),
+			"labels": labelsSchema(),
+			"default_labels_applied": defaultLabelsAppliedSchema(),
+			"label_map": labelMapSchema(),
+			"source_storage_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"source_snapshot_id", "template"},
+				Description:   "Create the storage as a clone of this storage. That it is in the same location and not larger than capacity is only checked on apply.",
+			},
+			"source_snapshot_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"source_storage_id", "template"},
+				Description:   "Create the storage as a clone of this snapshot. That it is in the same location and not larger than capacity is only checked on apply.",
+			},
+			"parent_uuid": {
+				Type:     schema.TypeString,
+				Computed: true,
 			},
-			"labels":{
-				Type: schema.TypeList,
-				Elem:     &schema.Schema{Type: schema.TypeString},
+			"template": {
+				Type:     schema.TypeList,
 				Optional: true,
+				ForceNew: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"template_uuid": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"template_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Name of the template, instead of template_uuid.",
+						},
+						"hostname": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"password": {
+							Type:      schema.TypeString,
+							Optional:  true,
+							Sensitive: true,
+						},
+						"password_type": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "plain",
+						},
+						"sshkeys": {
+							Type:     schema.TypeList,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Optional: true,
+						},
+					},
+				},
 			},
-		},
+		})),
 	}
 }
 
 func resourceGridScaleStorageCreate(d *schema.ResourceData, meta interface{}) error {
 	api_client := meta.(*Config)
+	if err := setResourceLocation(d, api_client); err != nil {
+		return err
+	}
+	if d.Get("source_storage_id").(string) != "" || d.Get("source_snapshot_id").(string) != "" {
+		return resourceGridScaleStorageCreateClone(d, meta)
+	}
+
+	template, err := storageTemplateParameters(d, api_client)
+	if err != nil {
+		return err
+	}
 	storage, err := api_client.CreateStorage(
 		d.Get("location_uuid").(string),
 		d.Get("name").(string),
 		d.Get("capacity").(int),
-		nil,
-		nil,
+		template,
+		objectLabels(d, api_client),
 	)
 	if err != nil {
 		return err
@@ -50,25 +129,156 @@ func resourceGridScaleStorageCreate(d *schema.ResourceData, meta interface{}) er
 	return resourceGridScaleStorageRead(d, meta)
 }
 
+// storageTemplateParameters returns the template block, or nil to create an
+// empty storage. A template_name is looked up in the cached template list.
+func storageTemplateParameters(d *schema.ResourceData, api_client *Config) (*gridscale.StorageTemplateParameters, error) {
+	templates := d.Get("template").([]interface{})
+	if len(templates) == 0 || templates[0] == nil {
+		return nil, nil
+	}
+
+	template := templates[0].(map[string]interface{})
+	templateId := template["template_uuid"].(string)
+	templateName := template["template_name"].(string)
+	if (templateId == "") == (templateName == "") {
+		return nil, fmt.Errorf("Exactly one of template_uuid and template_name must be set")
+	}
+	if templateName != "" {
+		t, err := api_client.getTemplateByName(templateName)
+		if err != nil {
+			return nil, err
+		}
+		templateId = t.ID
+	}
+
+	params := &gridscale.StorageTemplateParameters{
+		TemplateID: templateId,
+		Hostname:   template["hostname"].(string),
+		SSHKeyIDs:  toStringList(template["sshkeys"].([]interface{})),
+	}
+	if password := template["password"].(string); password != "" {
+		params.Password = password
+		params.PasswordType = template["password_type"].(string)
+	}
+	return params, nil
+}
+
+// resourceGridScaleStorageCreateClone creates the storage as a copy of
+// another storage or of a snapshot, then applies name and capacity.
+func resourceGridScaleStorageCreateClone(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	capacity := d.Get("capacity").(int)
+	locationId := d.Get("location_uuid").(string)
+
+	var storageId string
+	var sourceCapacity int
+	if snapshotId := d.Get("source_snapshot_id").(string); snapshotId != "" {
+		parentId, err := api_client.findSnapshotStorage(snapshotId)
+		if err != nil {
+			return err
+		}
+		snapshot, err := api_client.getSnapshot(parentId, snapshotId)
+		if err != nil {
+			return err
+		}
+		if err := validateCloneSource("snapshot "+snapshotId, snapshot.LocationID, snapshot.Capacity, locationId, capacity); err != nil {
+			return err
+		}
+		sourceCapacity = snapshot.Capacity
+		storageId, err = api_client.cloneSnapshot(parentId, snapshotId)
+		if err != nil {
+			return err
+		}
+	} else {
+		sourceId := d.Get("source_storage_id").(string)
+		source, err := api_client.GetStorage(sourceId)
+		if err != nil {
+			return err
+		}
+		if err := validateCloneSource("storage "+sourceId, source.LocationID, source.Capacity, locationId, capacity); err != nil {
+			return err
+		}
+		sourceCapacity = source.Capacity
+		storageId, err = api_client.cloneStorage(sourceId)
+		if err != nil {
+			return err
+		}
+	}
+	d.SetId(storageId)
+
+	err := waitForStatus("storage "+storageId, "active", d.Timeout(schema.TimeoutCreate), func() (string, error) {
+		storage, err := api_client.GetStorage(storageId)
+		if err != nil {
+			return "", err
+		}
+		return storage.Status, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The clone starts out with the name and size of its source.
+	if err := api_client.UpdateStorageName(storageId, d.Get("name").(string)); err != nil {
+		return err
+	}
+	if capacity > sourceCapacity {
+		if err := resizeStorage(api_client, storageId, capacity, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return err
+		}
+	}
+	if err := api_client.updateLabels("/objects/storages/"+storageId, objectLabels(d, api_client)); err != nil {
+		return err
+	}
+
+	return resourceGridScaleStorageRead(d, meta)
+}
+
 func resourceGridScaleStorageRead(d *schema.ResourceData, meta interface{}) error {
 	api_client := meta.(*Config)
 	storageId := d.Id()
 
-	storage, err := api_client.GetStorage(storageId)
+	storage, err := api_client.readStorage(storageId)
 	if err != nil {
 		return err
 	}
 
 	d.Set("name", storage.Name)
+	d.Set("location_uuid", storage.LocationID)
+	d.Set("capacity", storage.Capacity)
+	d.Set("parent_uuid", storage.ParentID)
+	protected, err := readObjectLabels(d, api_client, storage.Labels)
+	if err != nil {
+		return err
+	}
+	d.Set("delete_protection", protected)
+	readEstimatedCost(d, api_client, func(prices []gridscale.Price) (float64, error) {
+		return estimateStorageCost(prices, storage.Capacity)
+	})
+	setObjectMetadata(d, &storage.objectMetadata)
 	return nil
 }
 
 func resourceGridScaleStorageUpdate(d *schema.ResourceData, meta interface{}) error {
 	api_client := meta.(*Config)
 	storageId := d.Id()
+	api_client.batch.markStale(storageId)
+
+	if d.HasChange("capacity") {
+		oldCapacity, newCapacity := d.GetChange("capacity")
+		if err := checkStorageResize(oldCapacity.(int), newCapacity.(int)); err != nil {
+			return err
+		}
+	}
 
 	updateStorageName(d, api_client, storageId)
-	updateStorageCapacity(d, api_client, storageId)
+	if err := updateObjectLabels(d, api_client, "/objects/storages/"+storageId); err != nil {
+		return err
+	}
+	if d.HasChange("capacity") && d.Get("capacity").(int) != 0 {
+		if err := resizeStorage(api_client, storageId, d.Get("capacity").(int), d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
+		}
+	}
 
 	return resourceGridScaleStorageRead(d, meta)
 }
@@ -76,11 +286,34 @@ func resourceGridScaleStorageUpdate(d *schema.ResourceData, meta interface{}) er
 func resourceGridScaleStorageDelete(d *schema.ResourceData, meta interface{}) error {
 	api_client := meta.(*Config)
 	storageId := d.Id()
-	err := api_client.DeleteStorage(storageId)
+	timeout := d.Timeout(schema.TimeoutDelete)
 
+	storage, err := api_client.getStorage(storageId)
+	if isNotFound(err) {
+		log.Printf("[WARN] Storage %s not found, removing from state", storageId)
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
 		return err
 	}
+	if err := checkDeleteProtection("Storage "+storageId, storage.Labels); err != nil {
+		return err
+	}
+	serverIds := []string{}
+	for _, rel := range storage.Relations.Servers {
+		serverIds = append(serverIds, rel.ServerID)
+	}
+	err = detachFromServers(api_client, serverIds, timeout, func(serverId string) error {
+		return api_client.DisconnectStorage(storageId, serverId)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := deleteObject(api_client, "/objects/storages/"+storageId, timeout); err != nil {
+		return err
+	}
 	d.SetId("")
 
 	return nil
@@ -3,9 +3,11 @@ package gridscale
 import (
 	"testing"
 	"fmt"
+	"time"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
-	"github.com/parce-iot/gridscale"
+	"github.com/gridscale/terraform-provider-gridscale/internal/gridscale"
 )
 
 
@@ -39,6 +41,25 @@ func TestAccGridScaleStorage_Basic(t *testing.T) {
 	})
 }
 
+func TestAccGridScaleStorage_Clone(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {testAccPreCheck(t)},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDGridScaleStorageDestroyCheck,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckGridScaleStorageConfig_clone,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gridscale_storage.fromstorage", "name", "fromstorage"),
+					resource.TestCheckResourceAttr("gridscale_storage.fromstorage", "capacity", "2"),
+					resource.TestCheckResourceAttrSet("gridscale_storage.fromstorage", "parent_uuid"),
+					resource.TestCheckResourceAttr("gridscale_storage.fromsnapshot", "capacity", "1"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckDGridScaleStorageDestroyCheck(s *terraform.State) error {
 	client := testAccProvider.Meta().(*Config)
 	for _, rs := range s.RootModule().Resources {
@@ -109,3 +130,104 @@ resource "gridscale_storage" "teststorage" {
   capacity = "2"
   location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
 }`
+
+const testAccCheckGridScaleStorageConfig_clone = `
+resource "gridscale_storage" "golden" {
+  name = "golden"
+  capacity = "1"
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+}
+
+resource "gridscale_snapshot" "golden" {
+  storage_id = "${gridscale_storage.golden.id}"
+  name = "golden"
+}
+
+resource "gridscale_storage" "fromstorage" {
+  name = "fromstorage"
+  capacity = "2"
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+  source_storage_id = "${gridscale_storage.golden.id}"
+}
+
+resource "gridscale_storage" "fromsnapshot" {
+  name = "fromsnapshot"
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+  source_snapshot_id = "${gridscale_snapshot.golden.id}"
+}`
+
+func TestStorageTemplateParameters(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceGridScaleStorage().Schema, map[string]interface{}{
+		"name":          "fromtemplate",
+		"location_uuid": "45ed677b-3702-4b36-be2a-a2eab9827950",
+		"template": []interface{}{
+			map[string]interface{}{
+				"template_uuid": "template-uuid",
+				"hostname":      "web-1",
+				"password":      "secret",
+				"sshkeys":       []interface{}{"key-uuid"},
+			},
+		},
+	})
+
+	params, err := storageTemplateParameters(d, &Config{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if params == nil {
+		t.Fatal("expected template parameters")
+	}
+	if params.TemplateID != "template-uuid" || params.Hostname != "web-1" {
+		t.Fatalf("bad template parameters: %#v", params)
+	}
+	if params.Password != "secret" || params.PasswordType != "plain" {
+		t.Fatalf("bad password: %#v", params)
+	}
+	if len(params.SSHKeyIDs) != 1 || params.SSHKeyIDs[0] != "key-uuid" {
+		t.Fatalf("bad ssh keys: %#v", params.SSHKeyIDs)
+	}
+
+	empty := schema.TestResourceDataRaw(t, resourceGridScaleStorage().Schema, map[string]interface{}{
+		"name":          "empty",
+		"location_uuid": "45ed677b-3702-4b36-be2a-a2eab9827950",
+	})
+	if params, err := storageTemplateParameters(empty, &Config{}); params != nil || err != nil {
+		t.Fatalf("expected no template parameters, got %#v, %v", params, err)
+	}
+}
+
+func TestStorageTemplateParameters_name(t *testing.T) {
+	api_client := &Config{cache: newListCache(time.Minute)}
+	api_client.cache.get(cacheTemplates, func() (interface{}, error) {
+		return []gridscale.Template{
+			{ID: "debian-uuid", Name: "Debian 9"},
+			{ID: "ubuntu-uuid", Name: "Ubuntu 18.04"},
+		}, nil
+	})
+
+	config := func(template map[string]interface{}) *schema.ResourceData {
+		template["hostname"] = "web-1"
+		return schema.TestResourceDataRaw(t, resourceGridScaleStorage().Schema, map[string]interface{}{
+			"name":     "fromtemplate",
+			"template": []interface{}{template},
+		})
+	}
+
+	params, err := storageTemplateParameters(config(map[string]interface{}{"template_name": "Ubuntu 18.04"}), api_client)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if params.TemplateID != "ubuntu-uuid" {
+		t.Fatalf("expected the template to be looked up by name, got %q", params.TemplateID)
+	}
+
+	for _, template := range []map[string]interface{}{
+		{"template_name": "CentOS 7"},
+		{},
+		{"template_uuid": "debian-uuid", "template_name": "Debian 9"},
+	} {
+		if _, err := storageTemplateParameters(config(template), api_client); err == nil {
+			t.Errorf("expected an error for %v", template)
+		}
+	}
+}
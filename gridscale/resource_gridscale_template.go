@@ -0,0 +1,157 @@
+package gridscale
+
+import (
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceGridScaleTemplate() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceGridScaleTemplateCreate,
+		Read:   resourceGridScaleTemplateRead,
+		Update: resourceGridScaleTemplateUpdate,
+		Delete: resourceGridScaleTemplateDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"snapshot_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				// The API does not return the snapshot of a template, so
+				// it is unknown after an import and must not force a
+				// replacement.
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return old == "" && d.Id() != ""
+				},
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"labels":                 labelsSchema(),
+			"default_labels_applied": defaultLabelsAppliedSchema(),
+			"label_map":              labelMapSchema(),
+			"ostype": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"version": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"location_uuid": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"capacity": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"private": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceGridScaleTemplateCreate(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+
+	templateId, err := api_client.createTemplate(templateRequest{
+		Name:       d.Get("name").(string),
+		SnapshotID: d.Get("snapshot_id").(string),
+		Labels:     objectLabels(d, api_client),
+		OSType:     d.Get("ostype").(string),
+		Version:    d.Get("version").(string),
+	})
+	if err != nil {
+		return err
+	}
+	d.SetId(templateId)
+
+	err = waitForStatus("template "+templateId, "active", d.Timeout(schema.TimeoutCreate), func() (string, error) {
+		template, err := api_client.getTemplate(templateId)
+		if err != nil {
+			return "", err
+		}
+		return template.Status, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return resourceGridScaleTemplateRead(d, meta)
+}
+
+func resourceGridScaleTemplateRead(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+
+	template, err := api_client.getTemplate(d.Id())
+	if isNotFound(err) {
+		log.Printf("[WARN] Template %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("name", template.Name)
+	d.Set("ostype", template.OSType)
+	d.Set("version", template.Version)
+	d.Set("location_uuid", template.LocationID)
+	d.Set("capacity", template.Capacity)
+	d.Set("private", template.Private)
+	d.Set("status", template.Status)
+	if _, err := readObjectLabels(d, api_client, template.Labels); err != nil {
+		return err
+	}
+	return nil
+}
+
+func resourceGridScaleTemplateUpdate(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+
+	if d.HasChange("name") || d.HasChange("ostype") || d.HasChange("version") {
+		err := api_client.updateTemplate(d.Id(), templateRequest{
+			Name:    d.Get("name").(string),
+			OSType:  d.Get("ostype").(string),
+			Version: d.Get("version").(string),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := updateObjectLabels(d, api_client, "/objects/templates/"+d.Id()); err != nil {
+		return err
+	}
+
+	return resourceGridScaleTemplateRead(d, meta)
+}
+
+func resourceGridScaleTemplateDelete(d *schema.ResourceData, meta interface{}) error {
+	api_client := meta.(*Config)
+	err := api_client.deleteTemplate(d.Id())
+
+	if err != nil {
+		return err
+	}
+	d.SetId("")
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package gridscale
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAccGridScaleTemplate_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckDGridScaleTemplateDestroyCheck,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccCheckGridScaleTemplateConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("gridscale_template.golden", "name", "golden-image"),
+					resource.TestCheckResourceAttr("gridscale_template.golden", "private", "true"),
+					resource.TestCheckResourceAttr("gridscale_template.golden", "status", "active"),
+					resource.TestCheckResourceAttrSet("gridscale_storage.fromtemplate", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDGridScaleTemplateDestroyCheck(s *terraform.State) error {
+	client := testAccProvider.Meta().(*Config)
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gridscale_template" {
+			continue
+		}
+		_, err := client.getTemplate(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("Template %s still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+const testAccCheckGridScaleTemplateConfig_basic = `
+resource "gridscale_storage" "golden" {
+  name = "golden"
+  capacity = 1
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+}
+
+resource "gridscale_snapshot" "golden" {
+  storage_id = "${gridscale_storage.golden.id}"
+  name = "golden"
+}
+
+resource "gridscale_template" "golden" {
+  snapshot_id = "${gridscale_snapshot.golden.id}"
+  name = "golden-image"
+  ostype = "linux"
+  version = "1.0"
+}
+
+resource "gridscale_storage" "fromtemplate" {
+  name = "fromtemplate"
+  capacity = 1
+  location_uuid = "45ed677b-3702-4b36-be2a-a2eab9827950"
+  template {
+    template_uuid = "${gridscale_template.golden.id}"
+    hostname = "fromtemplate"
+  }
+}`
@@ -0,0 +1,135 @@
+package gridscale
+
+import (
+	"regexp"
+	"sync"
+	"time"
+)
+
+// serverJobTimeout bounds the wait for a server to finish the job started by
+// a locked call.
+var serverJobTimeout = 5 * time.Minute
+
+// keyedMutex hands out one mutex per key. The zero value is ready to use.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// lock locks the mutex of key and returns the function unlocking it.
+func (k *keyedMutex) lock(key string) func() {
+	k.mu.Lock()
+	if k.locks == nil {
+		k.locks = map[string]*sync.Mutex{}
+	}
+	l, ok := k.locks[key]
+	if !ok {
+		l = &sync.Mutex{}
+		k.locks[key] = l
+	}
+	k.mu.Unlock()
+
+	l.Lock()
+	return l.Unlock
+}
+
+// withServerLock runs call while holding the lock of the server, then waits
+// for the server to become active again. gridscale locks a server while it
+// processes a change and refuses further changes with 424 until it is done,
+// so parallel operations on one server queue up here instead.
+func (c *Config) withServerLock(serverID string, call func() error) error {
+	unlock := c.serverLocks.lock(serverID)
+	defer unlock()
+
+	if err := call(); err != nil {
+		return err
+	}
+	return waitForStatus("server "+serverID, "active", serverJobTimeout, func() (string, error) {
+		server, err := c.Client.GetServer(serverID)
+		if err != nil {
+			return "", err
+		}
+		return server.Status, nil
+	})
+}
+
+var serverPathRegexp = regexp.MustCompile(`^/objects/servers/([^/]+)`)
+
+// serverOfPath returns the UUID of the server a mutating API path changes.
+func serverOfPath(method, path string) (string, bool) {
+	if method == "GET" {
+		return "", false
+	}
+	m := serverPathRegexp.FindStringSubmatch(path)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// The methods below shadow the client library's calls that change a server,
+// so every caller goes through the server's lock.
+
+func (c *Config) UpdateServerName(serverID string, name string) error {
+	return c.withServerLock(serverID, func() error { return c.Client.UpdateServerName(serverID, name) })
+}
+
+func (c *Config) UpdateServerLabels(serverID string, labels []string) error {
+	return c.withServerLock(serverID, func() error { return c.Client.UpdateServerLabels(serverID, labels) })
+}
+
+func (c *Config) UpdateServerCores(serverID string, cores int) error {
+	return c.withServerLock(serverID, func() error { return c.Client.UpdateServerCores(serverID, cores) })
+}
+
+func (c *Config) UpdateServerMemory(serverID string, memoryGB int) error {
+	return c.withServerLock(serverID, func() error { return c.Client.UpdateServerMemory(serverID, memoryGB) })
+}
+
+func (c *Config) PowerOnServer(serverID string) error {
+	return c.withServerLock(serverID, func() error { return c.Client.PowerOnServer(serverID) })
+}
+
+func (c *Config) PowerOffServer(serverID string) error {
+	return c.withServerLock(serverID, func() error { return c.Client.PowerOffServer(serverID) })
+}
+
+func (c *Config) ConnectIPAddress(ipAddressID, serverID string) error {
+	return c.withServerLock(serverID, func() error { return c.Client.ConnectIPAddress(ipAddressID, serverID) })
+}
+
+func (c *Config) DisconnectIPAddress(ipAddressID, serverID string) error {
+	return c.withServerLock(serverID, func() error { return c.Client.DisconnectIPAddress(ipAddressID, serverID) })
+}
+
+func (c *Config) ConnectNetwork(networkID string, ordering int, serverID string) error {
+	return c.withServerLock(serverID, func() error { return c.Client.ConnectNetwork(networkID, ordering, serverID) })
+}
+
+func (c *Config) DisconnectNetwork(networkID, serverID string) error {
+	return c.withServerLock(serverID, func() error { return c.Client.DisconnectNetwork(networkID, serverID) })
+}
+
+func (c *Config) ConnectStorage(storageID string, bootdevice bool, serverID string) error {
+	return c.withServerLock(serverID, func() error { return c.Client.ConnectStorage(storageID, bootdevice, serverID) })
+}
+
+func (c *Config) DisconnectStorage(storageID, serverID string) error {
+	return c.withServerLock(serverID, func() error { return c.Client.DisconnectStorage(storageID, serverID) })
+}
+
+func (c *Config) ConnectIsoImage(isoImageID, serverID string) error {
+	return c.withServerLock(serverID, func() error { return c.Client.ConnectIsoImage(isoImageID, serverID) })
+}
+
+func (c *Config) DisconnectIsoImage(isoImageID, serverID string) error {
+	return c.withServerLock(serverID, func() error { return c.Client.DisconnectIsoImage(isoImageID, serverID) })
+}
+
+// DeleteServer waits for pending changes of the server. There is nothing
+// to wait for afterwards.
+func (c *Config) DeleteServer(serverID string) error {
+	unlock := c.serverLocks.lock(serverID)
+	defer unlock()
+	return c.Client.DeleteServer(serverID)
+}
@@ -0,0 +1,72 @@
+package gridscale
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeyedMutex(t *testing.T) {
+	var k keyedMutex
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := k.lock("server-1")
+			defer unlock()
+
+			mu.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mu.Unlock()
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if maxRunning != 1 {
+		t.Fatalf("expected calls for one server to run one at a time, got %d at once", maxRunning)
+	}
+
+	// Other keys are not blocked.
+	unlock := k.lock("server-1")
+	done := make(chan struct{})
+	go func() {
+		k.lock("server-2")()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected another server's lock to be independent")
+	}
+	unlock()
+}
+
+func TestServerOfPath(t *testing.T) {
+	cases := []struct {
+		method, path string
+		server       string
+		ok           bool
+	}{
+		{"PATCH", "/objects/servers/uuid-1", "uuid-1", true},
+		{"PATCH", "/objects/servers/uuid-1/storages/uuid-2", "uuid-1", true},
+		{"POST", "/objects/servers/uuid-1/isoimages", "uuid-1", true},
+		{"GET", "/objects/servers/uuid-1", "", false},
+		{"PATCH", "/objects/storages/uuid-2", "", false},
+	}
+	for _, tc := range cases {
+		server, ok := serverOfPath(tc.method, tc.path)
+		if server != tc.server || ok != tc.ok {
+			t.Errorf("serverOfPath(%q, %q) = %q, %t, expected %q, %t", tc.method, tc.path, server, ok, tc.server, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package gridscale
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// transportConfig holds the connection settings of the provider.
+type transportConfig struct {
+	ProxyURL   string
+	CACertFile string
+	ClientCert string
+	ClientKey  string
+	Insecure   bool
+}
+
+func (tc transportConfig) isDefault() bool {
+	return tc == transportConfig{}
+}
+
+// readPEM returns value itself if it holds PEM data, otherwise the content
+// of the file it names.
+func readPEM(value string) ([]byte, error) {
+	if strings.HasPrefix(strings.TrimSpace(value), "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return ioutil.ReadFile(expandHome(value))
+}
+
+// newTransport returns a transport with the settings of
+// http.DefaultTransport and the proxy and TLS settings applied.
+func newTransport(tc transportConfig) (*http.Transport, error) {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: tc.Insecure}
+
+	if tc.ProxyURL != "" {
+		proxy, err := url.Parse(tc.ProxyURL)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("proxy_url %q is not a valid URL", tc.ProxyURL)
+		}
+		// Credentials in the URL are sent as Proxy-Authorization.
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if tc.CACertFile != "" {
+		pem, err := ioutil.ReadFile(expandHome(tc.CACertFile))
+		if err != nil {
+			return nil, fmt.Errorf("Error reading ca_cert_file: %s", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca_cert_file %s contains no PEM certificates", tc.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if (tc.ClientCert == "") != (tc.ClientKey == "") {
+		return nil, fmt.Errorf("client_cert and client_key must be set together")
+	}
+	if tc.ClientCert != "" {
+		certPEM, err := readPEM(tc.ClientCert)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading client_cert: %s", err)
+		}
+		keyPEM, err := readPEM(tc.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading client_key: %s", err)
+		}
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid client certificate: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
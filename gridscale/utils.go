@@ -1,6 +1,19 @@
 package gridscale
 
-import "github.com/hashicorp/terraform/helper/schema"
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/gridscale/terraform-provider-gridscale/internal/gridscale"
+)
+
+// pollInterval is the time between two status checks while waiting for
+// asynchronous API jobs.
+var pollInterval = 5 * time.Second
 
 func updateServerName(d *schema.ResourceData, api_client *Config, serverId string) () {
 	if d.HasChange("name") {
@@ -32,15 +45,6 @@ func updateServerMemory(d *schema.ResourceData, api_client *Config, serverId str
 	}
 }
 
-func updateServerLables(d *schema.ResourceData, api_client *Config, serverId string) () {
-	if d.HasChange("lables") {
-		api_client.UpdateServerLabels(
-			serverId,
-			d.Get("lables").([]string),
-		)
-	}
-}
-
 func updateServerPower(d *schema.ResourceData, api_client *Config, serverId string) () {
 
 	if d.HasChange("power_on") {
@@ -70,25 +74,106 @@ func updateServerNetwork(d *schema.ResourceData, api_client *Config, serverId st
 		}
 	}
 }
-func updateServerStorage(d *schema.ResourceData, api_client *Config, serverId string) () {
+// storageAttachment is a storage connected to a server.
+type storageAttachment struct {
+	storageId  string
+	bootdevice bool
+}
 
-	api_client.ConnectStorage(
-		d.Get("storage_id").(string),
-		d.Get("bootdevice").(bool),
-		serverId,
-	)
+// storageChanges lists what has to be done to get from one set of storage
+// attachments to another.
+type storageChanges struct {
+	disconnect []storageAttachment
+	connect    []storageAttachment
+	update     []storageAttachment
+}
 
-	api_client.DisconnectStorage(
-		d.Get("storage_id").(string),
-		serverId,
-	)
+// diffStorageAttachments compares the attached storages before and after a
+// change. Storages present on both sides are only updated if their boot
+// device flag differs, they are never detached and reattached.
+func diffStorageAttachments(oldAttachments, newAttachments []storageAttachment) storageChanges {
+	changes := storageChanges{}
+
+	oldById := map[string]storageAttachment{}
+	for _, a := range oldAttachments {
+		oldById[a.storageId] = a
+	}
+	newById := map[string]storageAttachment{}
+	for _, a := range newAttachments {
+		newById[a.storageId] = a
+	}
+
+	for _, a := range oldAttachments {
+		if _, ok := newById[a.storageId]; !ok {
+			changes.disconnect = append(changes.disconnect, a)
+		}
+	}
+	for _, a := range newAttachments {
+		old, ok := oldById[a.storageId]
+		if !ok {
+			changes.connect = append(changes.connect, a)
+		} else if old.bootdevice != a.bootdevice {
+			changes.update = append(changes.update, a)
+		}
+	}
+	return changes
 }
 
-func updateServerIsoImage(d *schema.ResourceData, api_client *Config, serverId string) () {
-	api_client.DisconnectIsoImage(
-		d.Get("iso_image_id").(string),
-		serverId,
+func serverStorageAttachments(storageId interface{}, bootdevice interface{}) []storageAttachment {
+	if storageId.(string) == "" {
+		return nil
+	}
+	return []storageAttachment{{storageId: storageId.(string), bootdevice: bootdevice.(bool)}}
+}
+
+// updateServerStorage reconciles the storage given by storage_id and
+// bootdevice with what is attached to the server.
+func updateServerStorage(d *schema.ResourceData, api_client *Config, serverId string) error {
+	if !d.HasChange("storage_id") && !d.HasChange("bootdevice") {
+		return nil
+	}
+
+	oldStorage, newStorage := d.GetChange("storage_id")
+	oldBoot, newBoot := d.GetChange("bootdevice")
+	changes := diffStorageAttachments(
+		serverStorageAttachments(oldStorage, oldBoot),
+		serverStorageAttachments(newStorage, newBoot),
 	)
+
+	for _, a := range changes.disconnect {
+		if err := api_client.DisconnectStorage(a.storageId, serverId); err != nil {
+			return err
+		}
+	}
+	for _, a := range changes.update {
+		if err := api_client.updateServerStorageRelation(serverId, a.storageId, a.bootdevice); err != nil {
+			return err
+		}
+	}
+	for _, a := range changes.connect {
+		if err := api_client.ConnectStorage(a.storageId, a.bootdevice, serverId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateServerIsoImage mounts, swaps or unmounts the server's ISO image.
+func updateServerIsoImage(d *schema.ResourceData, api_client *Config, serverId string) error {
+	if !d.HasChange("iso_image_id") {
+		return nil
+	}
+
+	oldImage, newImage := d.GetChange("iso_image_id")
+	if oldImage.(string) != "" {
+		if err := api_client.DisconnectIsoImage(oldImage.(string), serverId); err != nil {
+			return err
+		}
+	}
+	if newImage.(string) != "" {
+		return api_client.ConnectIsoImage(newImage.(string), serverId)
+	}
+	return nil
 }
 
 func updateNetworkName(d *schema.ResourceData, api_client *Config, networkId string) () {
@@ -100,15 +185,6 @@ func updateNetworkName(d *schema.ResourceData, api_client *Config, networkId str
 	}
 }
 
-func updateNetworkLabels(d *schema.ResourceData, api_client *Config, networkId string) () {
-	if d.HasChange("labels") {
-		api_client.UpdateNetworkLabels(
-			networkId,
-			d.Get("labels").([]string),
-		)
-	}
-}
-
 
 func updateStorageName(d *schema.ResourceData, api_client *Config, storageId string) () {
 	if d.HasChange("name") {
@@ -119,11 +195,545 @@ func updateStorageName(d *schema.ResourceData, api_client *Config, storageId str
 	}
 }
 
-func updateStorageCapacity(d *schema.ResourceData, api_client *Config, storageId string) () {
-	if d.HasChange("capacity") {
-		api_client.UpdateStorageCapacity(
-			storageId,
-			d.Get("capacity").(int),
-		)
+// checkStorageResize refuses to change a storage from oldCapacity to
+// newCapacity unless it grows. Shrinking would destroy the data, so it needs
+// an explicit replacement that shows up in the plan.
+func checkStorageResize(oldCapacity, newCapacity int) error {
+	if newCapacity == 0 || newCapacity >= oldCapacity {
+		return nil
+	}
+	return fmt.Errorf(
+		"Cannot shrink storage from %d GB to %d GB, storages can only grow. "+
+			"To replace it with a new, empty storage, taint it with terraform taint or change an argument that forces a new resource",
+		oldCapacity, newCapacity)
+}
+
+// resizeStorage grows a storage to capacity GB and waits until the resize
+// is done.
+func resizeStorage(api_client *Config, storageId string, capacity int, timeout time.Duration) error {
+	if err := api_client.UpdateStorageCapacity(storageId, capacity); err != nil {
+		return err
+	}
+
+	return waitForStatus("resize of storage "+storageId, "done", timeout, func() (string, error) {
+		storage, err := api_client.GetStorage(storageId)
+		if err != nil {
+			return "", err
+		}
+		if storage.Status == "active" && storage.Capacity == capacity {
+			return "done", nil
+		}
+		return storage.Status, nil
+	})
+}
+
+func validateServerPublicIPs(d *schema.ResourceData) error {
+	if d.Get("public_network").(bool) {
+		return nil
+	}
+	for _, key := range []string{"auto_assign_ipv4", "auto_assign_ipv6"} {
+		if d.Get(key).(bool) {
+			return fmt.Errorf("%s requires public_network to be enabled", key)
+		}
+	}
+	return nil
+}
+
+func updateServerPublicNetwork(d *schema.ResourceData, api_client *Config, serverId string) error {
+	if !d.HasChange("public_network") {
+		return nil
+	}
+
+	network, err := api_client.getPublicNetwork(d.Get("location_uuid").(string))
+	if err != nil {
+		return err
+	}
+
+	if d.Get("public_network").(bool) {
+		return api_client.ConnectNetwork(network.ID, 0, serverId)
+	}
+	return api_client.DisconnectNetwork(network.ID, serverId)
+}
+
+// updateServerPublicIP allocates or releases the IP address of the given
+// family (4 or 6) managed through auto_assign_ipv4/auto_assign_ipv6.
+func updateServerPublicIP(d *schema.ResourceData, api_client *Config, serverId string, family int) error {
+	assignKey := fmt.Sprintf("auto_assign_ipv%d", family)
+	idKey := fmt.Sprintf("ipv%d_id", family)
+	if !d.HasChange(assignKey) {
+		return nil
+	}
+
+	if !d.Get(assignKey).(bool) {
+		ipId := d.Get(idKey).(string)
+		if ipId == "" {
+			return nil
+		}
+		if err := api_client.DisconnectIPAddress(ipId, serverId); err != nil {
+			return err
+		}
+		if err := api_client.DeleteIP(ipId); err != nil {
+			return err
+		}
+		d.Set(idKey, "")
+		d.Set(fmt.Sprintf("ipv%d_address", family), "")
+		return nil
+	}
+
+	// An address that was disconnected outside of Terraform is still
+	// billed, so it is connected again rather than replaced.
+	if ipId := d.Get(idKey).(string); ipId != "" {
+		err := api_client.apiCall("GET", "/objects/ips/"+ipId, nil, nil)
+		if err == nil {
+			log.Printf("[INFO] Reconnecting IP %s to server %s", ipId, serverId)
+			return api_client.ConnectIPAddress(ipId, serverId)
+		}
+		if !isNotFound(err) {
+			return err
+		}
+	}
+
+	create := api_client.CreateIPv4
+	if family == 6 {
+		create = api_client.CreateIPv6
+	}
+	ip, err := create(d.Get("location_uuid").(string), false, nil, nil)
+	if err != nil {
+		return err
+	}
+	d.Set(idKey, ip.ID)
+
+	return api_client.ConnectIPAddress(ip.ID, serverId)
+}
+
+// readServerPublicIP refreshes the address managed through auto_assign_ipv4/6.
+// If the address is no longer connected, auto_assign is cleared so the next
+// apply connects it again. Its ID is kept, so the address is reused or,
+// when the server is destroyed, deleted.
+func readServerPublicIP(d *schema.ResourceData, relations *serverRelations, family int) {
+	idKey := fmt.Sprintf("ipv%d_id", family)
+	addressKey := fmt.Sprintf("ipv%d_address", family)
+
+	ipId := d.Get(idKey).(string)
+	if ipId == "" {
+		return
+	}
+	for _, rel := range relations.PublicIPs {
+		if rel.IPID == ipId {
+			d.Set(addressKey, rel.IP)
+			return
+		}
+	}
+	d.Set(addressKey, "")
+	d.Set(fmt.Sprintf("auto_assign_ipv%d", family), false)
+}
+
+func toStringList(list []interface{}) []string {
+	result := make([]string, 0, len(list))
+	for _, v := range list {
+		result = append(result, v.(string))
+	}
+	return result
+}
+
+// attachmentId builds the ID of an attachment resource from the server and
+// the attached object.
+func attachmentId(serverId, objectId string) string {
+	return serverId + "/" + objectId
+}
+
+// parseAttachmentId splits an attachment ID of the form "server_id/object_id".
+func parseAttachmentId(id string) (string, string, error) {
+	return splitCompositeId(id, "server_id/object_id")
+}
+
+// splitCompositeId splits an ID made of two parts separated by a slash.
+// format describes the expected form for the error message.
+func splitCompositeId(id string, format string) (string, string, error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("Invalid ID %q, expected %s", id, format)
+	}
+	return parts[0], parts[1], nil
+}
+
+// waitForStatus polls the status of an object until it reaches target. It
+// fails early if the object ends up in the "error" status.
+func waitForStatus(description string, target string, timeout time.Duration, status func() (string, error)) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		current, err := status()
+		if err != nil {
+			return err
+		}
+		if current == target {
+			return nil
+		}
+		if current == "error" {
+			return fmt.Errorf("%s ended up in status %q", description, current)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Timeout after %s waiting for %s to become %q, last status %q", timeout, description, target, current)
+		}
+		log.Printf("[DEBUG] Waiting for %s to become %q, current status %q", description, target, current)
+		time.Sleep(pollInterval)
+	}
+}
+
+// validateCloneSource checks that a clone can be created from the source:
+// it has to be in the same location and may not be smaller than the source.
+// A capacity of 0 keeps the size of the source.
+func validateCloneSource(source string, sourceLocation string, sourceCapacity int, location string, capacity int) error {
+	if sourceLocation != "" && sourceLocation != location {
+		return fmt.Errorf("Cannot clone %s from location %s into location %s", source, sourceLocation, location)
+	}
+	if capacity != 0 && capacity < sourceCapacity {
+		return fmt.Errorf("capacity %d GB is smaller than the %d GB of %s", capacity, sourceCapacity, source)
+	}
+	return nil
+}
+
+func powerStatus(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+// setServerPower switches a server on or off and waits until the change is done.
+func setServerPower(api_client *Config, serverId string, on bool, timeout time.Duration) error {
+	var err error
+	if on {
+		err = api_client.PowerOnServer(serverId)
+	} else {
+		err = api_client.PowerOffServer(serverId)
+	}
+	if err != nil {
+		return err
+	}
+
+	return waitForStatus("power of server "+serverId, powerStatus(on), timeout, func() (string, error) {
+		server, err := api_client.GetServer(serverId)
+		if err != nil {
+			return "", err
+		}
+		return powerStatus(server.Power), nil
+	})
+}
+
+// waitForDeletion polls get until it reports the object as not found.
+func waitForDeletion(description string, timeout time.Duration, get func() error) error {
+	return waitForStatus(description, "deleted", timeout, func() (string, error) {
+		err := get()
+		if isNotFound(err) {
+			return "deleted", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		return "deleting", nil
+	})
+}
+
+// waitForObjectDeletion waits until the object at path is gone from the API.
+func waitForObjectDeletion(api_client *Config, path string, timeout time.Duration) error {
+	return waitForDeletion("deletion of "+path, timeout, func() error {
+		return api_client.apiCall("GET", path, nil, nil)
+	})
+}
+
+// deleteObject deletes the object at path and waits until it is gone. An
+// object that is already gone counts as deleted.
+func deleteObject(api_client *Config, path string, timeout time.Duration) error {
+	err := api_client.apiCall("DELETE", path, nil, nil)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	return waitForObjectDeletion(api_client, path, timeout)
+}
+
+// powerOffServers switches off the running servers among serverIds and
+// returns the ones it switched off, so they can be powered on again.
+func powerOffServers(api_client *Config, serverIds []string, timeout time.Duration) ([]string, error) {
+	running := []string{}
+	for _, serverId := range serverIds {
+		server, err := api_client.GetServer(serverId)
+		if err != nil {
+			return running, err
+		}
+		if !server.Power {
+			continue
+		}
+		log.Printf("[INFO] Powering off server %s", serverId)
+		if err := setServerPower(api_client, serverId, false, timeout); err != nil {
+			return running, err
+		}
+		running = append(running, serverId)
+	}
+	return running, nil
+}
+
+func powerOnServers(api_client *Config, serverIds []string, timeout time.Duration) error {
+	for _, serverId := range serverIds {
+		log.Printf("[INFO] Powering on server %s", serverId)
+		if err := setServerPower(api_client, serverId, true, timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// detachFromServers calls detach for each server while the server is
+// powered off. Servers that were running are powered on again afterwards.
+func detachFromServers(api_client *Config, serverIds []string, timeout time.Duration, detach func(serverId string) error) error {
+	running, err := powerOffServers(api_client, serverIds, timeout)
+	if err == nil {
+		for _, serverId := range serverIds {
+			if err = detach(serverId); err != nil {
+				break
+			}
+		}
+	}
+	if powerErr := powerOnServers(api_client, running, timeout); err == nil {
+		err = powerErr
+	}
+	return err
+}
+
+// deleteProtectionLabel marks an object that must not be deleted. It is kept
+// on the object rather than in the state, so it survives the loss of the
+// state and shows up in the panel.
+const deleteProtectionLabel = "delete-protection"
+
+func deleteProtectionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Default:     false,
+		Description: "Refuse to delete the object while this is set.",
+	}
+}
+
+func labelsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+		Set:      schema.HashString,
+		Optional: true,
+	}
+}
+
+func labelMapSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeMap,
+		Elem:         &schema.Schema{Type: schema.TypeString},
+		Optional:     true,
+		ValidateFunc: validateLabelMap,
+		Description:  "Labels as key/value pairs, stored on the object as key=value.",
+	}
+}
+
+// defaultLabelsAppliedSchema tracks whether the object carries all of the
+// provider's default_labels. Read sets it to false when one is missing, so
+// the plan shows an update that adds it. Only the Default of an optional
+// attribute can produce that diff, a computed one is left alone when it is
+// missing from the config. Setting it in the config is rejected instead.
+func defaultLabelsAppliedSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeBool,
+		Optional:     true,
+		Default:      true,
+		ValidateFunc: validateDefaultLabelsApplied,
+		Description:  "Internal bookkeeping of the provider, cannot be set. False while default_labels are missing on the object.",
+	}
+}
+
+// validateDefaultLabelsApplied rejects any configured value. The Default
+// is not validated, so only the provider ever sets the attribute.
+func validateDefaultLabelsApplied(v interface{}, k string) (ws []string, errors []error) {
+	errors = append(errors, fmt.Errorf("%q is managed by the provider and cannot be set", k))
+	return
+}
+
+func validateLabelMap(v interface{}, k string) (ws []string, errors []error) {
+	for key := range v.(map[string]interface{}) {
+		if key == "" || strings.Contains(key, "=") {
+			errors = append(errors, fmt.Errorf("%q keys must not be empty or contain \"=\", got %q", k, key))
+		}
+	}
+	return
+}
+
+// parseLabel splits a key=value label. Labels without "=" are no key/value
+// pairs.
+func parseLabel(label string) (string, string, bool) {
+	i := strings.Index(label, "=")
+	if i < 1 {
+		return "", "", false
+	}
+	return label[:i], label[i+1:], true
+}
+
+func toStringMap(m map[string]interface{}) map[string]string {
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = v.(string)
+	}
+	return result
+}
+
+// objectLabels returns the labels to store on an object: the configured
+// labels and label_map, the provider's default labels and the ones the
+// provider keeps there for itself.
+func objectLabels(d *schema.ResourceData, api_client *Config) []string {
+	labels := []string{}
+	add := func(label string) {
+		if !containsString(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	for _, label := range toStringList(d.Get("labels").(*schema.Set).List()) {
+		add(label)
+	}
+	for key, value := range toStringMap(d.Get("label_map").(map[string]interface{})) {
+		add(key + "=" + value)
+	}
+	for _, label := range api_client.DefaultLabels {
+		add(label)
+	}
+	if v, ok := d.GetOk("delete_protection"); ok && v.(bool) {
+		add(deleteProtectionLabel)
+	}
+	sort.Strings(labels)
+	return labels
+}
+
+// objectLabelParts are the labels of an object split up the way a resource
+// shows them.
+type objectLabelParts struct {
+	labels          []string
+	labelMap        map[string]string
+	protected       bool
+	missingDefaults bool
+}
+
+// splitObjectLabels separates the labels of an object into the labels and
+// label_map attributes and the delete protection flag. A key=value label
+// stays in labels if it is configured there, and goes to label_map
+// otherwise. Default labels are left out unless the configuration lists
+// them as well, so they do not show up as a diff. Default labels missing on
+// the object are reported instead.
+func splitObjectLabels(labels, configured []string, configuredMap map[string]string, defaults []string) objectLabelParts {
+	parts := objectLabelParts{labels: []string{}, labelMap: map[string]string{}}
+	for _, label := range labels {
+		key, value, isPair := parseLabel(label)
+		configuredValue, inMap := configuredMap[key]
+		switch {
+		case label == deleteProtectionLabel:
+			parts.protected = true
+		case containsString(configured, label):
+			parts.labels = append(parts.labels, label)
+		case isPair && inMap && configuredValue == value:
+			parts.labelMap[key] = value
+		case containsString(defaults, label):
+		case isPair:
+			parts.labelMap[key] = value
+		default:
+			parts.labels = append(parts.labels, label)
+		}
+	}
+	for _, label := range defaults {
+		if !containsString(labels, label) {
+			parts.missingDefaults = true
+		}
+	}
+	return parts
+}
+
+// readObjectLabels sets labels and label_map from the labels of an object
+// and reports whether the object is delete protected.
+func readObjectLabels(d *schema.ResourceData, api_client *Config, labels []string) (bool, error) {
+	parts := splitObjectLabels(
+		labels,
+		toStringList(d.Get("labels").(*schema.Set).List()),
+		toStringMap(d.Get("label_map").(map[string]interface{})),
+		api_client.DefaultLabels,
+	)
+	if err := d.Set("labels", parts.labels); err != nil {
+		return parts.protected, err
+	}
+	if err := d.Set("default_labels_applied", !parts.missingDefaults); err != nil {
+		return parts.protected, err
+	}
+	return parts.protected, d.Set("label_map", parts.labelMap)
+}
+
+// updateObjectLabels writes the labels of the object at path if the
+// configured labels, the default labels or the delete protection changed.
+func updateObjectLabels(d *schema.ResourceData, api_client *Config, path string) error {
+	if !d.HasChange("labels") && !d.HasChange("label_map") && !d.HasChange("delete_protection") &&
+		!d.HasChange("default_labels_applied") {
+		return nil
+	}
+	return api_client.updateLabels(path, objectLabels(d, api_client))
+}
+
+// checkDeleteProtection refuses the deletion of an object whose labels carry
+// the delete protection. The labels are read from the API, so a lost or
+// stale state cannot bypass it.
+func checkDeleteProtection(description string, labels []string) error {
+	if containsString(labels, deleteProtectionLabel) {
+		return fmt.Errorf("%s has delete_protection enabled. Set delete_protection to false and apply before destroying it", description)
+	}
+	return nil
+}
+
+// findLocation returns the UUID of the location identified by ref, which
+// may be its UUID, IATA code or name.
+func findLocation(locations []gridscale.Location, ref string) (string, error) {
+	for _, location := range locations {
+		if location.ID == ref {
+			return location.ID, nil
+		}
+	}
+
+	matches := []string{}
+	for _, location := range locations {
+		if strings.EqualFold(location.Iata, ref) || strings.EqualFold(location.Name, ref) {
+			matches = append(matches, location.ID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("Location %q not found, expected a location UUID, IATA code or name", ref)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("Location %q is ambiguous, it matches locations %s", ref, strings.Join(matches, ", "))
+}
+
+// resourceLocation returns the configured location_uuid, or the provider's
+// default_location if it is not set.
+func resourceLocation(d *schema.ResourceData, api_client *Config) (string, error) {
+	if v, ok := d.GetOk("location_uuid"); ok {
+		return v.(string), nil
+	}
+	locationId, err := api_client.defaultLocationUUID()
+	if err != nil {
+		return "", err
+	}
+	if locationId == "" {
+		return "", fmt.Errorf("location_uuid is not set and the provider has no default_location")
+	}
+	return locationId, nil
+}
+
+// setResourceLocation resolves the location of a new object and stores it
+// in location_uuid for the rest of Create.
+func setResourceLocation(d *schema.ResourceData, api_client *Config) error {
+	locationId, err := resourceLocation(d, api_client)
+	if err != nil {
+		return err
 	}
+	return d.Set("location_uuid", locationId)
 }
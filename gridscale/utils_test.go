@@ -0,0 +1,530 @@
+package gridscale
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gridscale/terraform-provider-gridscale/internal/gridscale"
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestParseAttachmentId(t *testing.T) {
+	serverId, objectId, err := parseAttachmentId(attachmentId("server-uuid", "object-uuid"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if serverId != "server-uuid" || objectId != "object-uuid" {
+		t.Fatalf("bad split: %q, %q", serverId, objectId)
+	}
+
+	for _, id := range []string{"", "server-uuid", "server-uuid/", "/object-uuid", "a/b/c"} {
+		if _, _, err := parseAttachmentId(id); err == nil {
+			t.Errorf("expected an error for %q", id)
+		}
+	}
+}
+
+func TestDiffStorageAttachments(t *testing.T) {
+	boot := storageAttachment{storageId: "storage-1", bootdevice: true}
+	data := storageAttachment{storageId: "storage-1", bootdevice: false}
+	other := storageAttachment{storageId: "storage-2", bootdevice: true}
+
+	cases := []struct {
+		name       string
+		old, new   []storageAttachment
+		disconnect []storageAttachment
+		connect    []storageAttachment
+		update     []storageAttachment
+	}{
+		{name: "unchanged", old: []storageAttachment{boot}, new: []storageAttachment{boot}},
+		{name: "attach", new: []storageAttachment{boot}, connect: []storageAttachment{boot}},
+		{name: "detach", old: []storageAttachment{boot}, disconnect: []storageAttachment{boot}},
+		{name: "toggle bootdevice", old: []storageAttachment{boot}, new: []storageAttachment{data}, update: []storageAttachment{data}},
+		{
+			name:       "swap storage",
+			old:        []storageAttachment{boot},
+			new:        []storageAttachment{other},
+			disconnect: []storageAttachment{boot},
+			connect:    []storageAttachment{other},
+		},
+	}
+
+	for _, tc := range cases {
+		changes := diffStorageAttachments(tc.old, tc.new)
+		if !reflect.DeepEqual(changes.disconnect, tc.disconnect) {
+			t.Errorf("%s: disconnect = %v, expected %v", tc.name, changes.disconnect, tc.disconnect)
+		}
+		if !reflect.DeepEqual(changes.connect, tc.connect) {
+			t.Errorf("%s: connect = %v, expected %v", tc.name, changes.connect, tc.connect)
+		}
+		if !reflect.DeepEqual(changes.update, tc.update) {
+			t.Errorf("%s: update = %v, expected %v", tc.name, changes.update, tc.update)
+		}
+	}
+}
+
+func TestServerStorageAttachments(t *testing.T) {
+	if a := serverStorageAttachments("", true); a != nil {
+		t.Fatalf("expected no attachment without storage_id, got %v", a)
+	}
+
+	a := serverStorageAttachments("storage-1", true)
+	expected := []storageAttachment{{storageId: "storage-1", bootdevice: true}}
+	if !reflect.DeepEqual(a, expected) {
+		t.Fatalf("got %v, expected %v", a, expected)
+	}
+}
+
+func TestWaitForStatus(t *testing.T) {
+	defer func(interval time.Duration) { pollInterval = interval }(pollInterval)
+	pollInterval = 0
+
+	statuses := []string{"in-provisioning", "in-provisioning", "active"}
+	calls := 0
+	err := waitForStatus("test object", "active", time.Minute, func() (string, error) {
+		status := statuses[calls]
+		calls++
+		return status, nil
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 status checks, got %d", calls)
+	}
+
+	err = waitForStatus("test object", "active", time.Minute, func() (string, error) {
+		return "error", nil
+	})
+	if err == nil {
+		t.Fatal("expected an error for an object in error status")
+	}
+
+	err = waitForStatus("test object", "active", 0, func() (string, error) {
+		return "in-provisioning", nil
+	})
+	if err == nil {
+		t.Fatal("expected a timeout error")
+	}
+}
+
+func TestValidateCloneSource(t *testing.T) {
+	cases := []struct {
+		sourceLocation string
+		sourceCapacity int
+		location       string
+		capacity       int
+		ok             bool
+	}{
+		{"loc-1", 10, "loc-1", 0, true},
+		{"loc-1", 10, "loc-1", 10, true},
+		{"loc-1", 10, "loc-1", 20, true},
+		{"loc-1", 10, "loc-1", 5, false},
+		{"loc-1", 10, "loc-2", 10, false},
+	}
+	for _, tc := range cases {
+		err := validateCloneSource("storage src", tc.sourceLocation, tc.sourceCapacity, tc.location, tc.capacity)
+		if (err == nil) != tc.ok {
+			t.Errorf("validateCloneSource(%q, %d, %q, %d) = %v, expected ok=%t",
+				tc.sourceLocation, tc.sourceCapacity, tc.location, tc.capacity, err, tc.ok)
+		}
+	}
+}
+
+func TestCheckStorageResize(t *testing.T) {
+	cases := []struct {
+		old, new int
+		err      bool
+	}{
+		{10, 10, false},
+		{10, 0, false},
+		{10, 20, false},
+		{20, 10, true},
+	}
+	for _, tc := range cases {
+		err := checkStorageResize(tc.old, tc.new)
+		if (err != nil) != tc.err {
+			t.Errorf("checkStorageResize(%d, %d) error = %v, expected error %t", tc.old, tc.new, err, tc.err)
+		}
+	}
+}
+
+func TestResizeStorage(t *testing.T) {
+	defer func(interval time.Duration) { pollInterval = interval }(pollInterval)
+	pollInterval = 0
+
+	capacity, gets := 10, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			w.WriteHeader(204)
+			return
+		}
+		// The new capacity only shows up after a few polls.
+		gets++
+		status := "in-provisioning"
+		if gets == 3 {
+			status, capacity = "active", 20
+		}
+		w.Write([]byte(`{"storage": {"object_uuid": "storage-uuid", "status": "` + status + `", "capacity": ` + strconv.Itoa(capacity) + `}}`))
+	}))
+	defer server.Close()
+
+	client, _ := gridscale.NewClient("user-uuid", "token", server.URL)
+	api_client := &Config{Client: client, Endpoint: server.URL}
+
+	if err := resizeStorage(api_client, "storage-uuid", 20, time.Minute); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if gets != 3 {
+		t.Fatalf("expected resizeStorage to wait for the new capacity, got %d polls", gets)
+	}
+}
+
+func TestWaitForDeletion(t *testing.T) {
+	defer func(interval time.Duration) { pollInterval = interval }(pollInterval)
+	pollInterval = 0
+
+	calls := 0
+	err := waitForDeletion("test object", time.Minute, func() error {
+		calls++
+		if calls < 3 {
+			return nil
+		}
+		return &apiError{StatusCode: 404}
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 checks, got %d", calls)
+	}
+
+	err = waitForDeletion("test object", time.Minute, func() error {
+		return &apiError{StatusCode: 500}
+	})
+	if err == nil {
+		t.Fatal("expected the API error to be returned")
+	}
+}
+
+func TestDelete_alreadyGone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+	}))
+	defer server.Close()
+	api_client := &Config{Endpoint: server.URL}
+
+	for _, r := range []*schema.Resource{
+		resourceGridScaleServer(),
+		resourceGridScaleStorage(),
+		resourceGridScaleNetwork(),
+		resourceGridScaleIP(),
+	} {
+		state := &terraform.InstanceState{ID: "object-uuid", Attributes: map[string]string{}}
+		if _, err := r.Apply(state, &terraform.InstanceDiff{Destroy: true}, api_client); err != nil {
+			t.Fatalf("expected an object that is already gone to count as deleted, got %s", err)
+		}
+	}
+
+	// The object can also disappear between the lookup and the deletion.
+	if err := deleteObject(api_client, "/objects/storages/object-uuid", time.Minute); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestSplitObjectLabels(t *testing.T) {
+	parts := splitObjectLabels([]string{"db", deleteProtectionLabel, "prod"}, nil, nil, nil)
+	if !parts.protected {
+		t.Fatal("expected the delete protection label to be found")
+	}
+	if len(parts.labels) != 2 || parts.labels[0] != "db" || parts.labels[1] != "prod" {
+		t.Fatalf("unexpected labels %v", parts.labels)
+	}
+
+	parts = splitObjectLabels(nil, nil, nil, nil)
+	if parts.protected || len(parts.labels) != 0 || len(parts.labelMap) != 0 {
+		t.Fatalf("expected no labels and no protection, got %+v", parts)
+	}
+
+	// Default labels only show up when they are configured explicitly.
+	defaults := []string{"managed-by=terraform", "team=ops"}
+	parts = splitObjectLabels([]string{"db", "managed-by=terraform", "team=ops"}, []string{"db", "team=ops"}, nil, defaults)
+	if len(parts.labels) != 2 || parts.labels[0] != "db" || parts.labels[1] != "team=ops" || len(parts.labelMap) != 0 {
+		t.Fatalf("unexpected labels %+v", parts)
+	}
+	if parts.missingDefaults {
+		t.Fatal("expected all default labels to be present")
+	}
+
+	// A default label added to the provider after the object was created is
+	// reported, so the next apply adds it.
+	parts = splitObjectLabels([]string{"db", "managed-by=terraform"}, []string{"db"}, nil, defaults)
+	if !parts.missingDefaults {
+		t.Fatal("expected the missing default label team=ops to be reported")
+	}
+
+	// key=value labels go to label_map unless they are configured as
+	// plain labels.
+	parts = splitObjectLabels(
+		[]string{"db", "env=prod", "owner=payments", "team=ops", "managed-by=terraform"},
+		[]string{"db", "env=prod"},
+		map[string]string{"owner": "payments", "team": "ops"},
+		defaults,
+	)
+	if len(parts.labels) != 2 || parts.labels[0] != "db" || parts.labels[1] != "env=prod" {
+		t.Fatalf("unexpected labels %v", parts.labels)
+	}
+	if len(parts.labelMap) != 2 || parts.labelMap["owner"] != "payments" || parts.labelMap["team"] != "ops" {
+		t.Fatalf("unexpected label map %v", parts.labelMap)
+	}
+
+	// Labels set outside of Terraform show up as drift in label_map.
+	parts = splitObjectLabels([]string{"env=test"}, nil, nil, nil)
+	if parts.labelMap["env"] != "test" {
+		t.Fatalf("unexpected label map %v", parts.labelMap)
+	}
+}
+
+func TestDefaultLabelsApplied(t *testing.T) {
+	r := resourceGridScaleNetwork()
+	newConfig := func(raw map[string]interface{}) *terraform.ResourceConfig {
+		c, err := config.NewRawConfig(raw)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		return terraform.NewResourceConfig(c)
+	}
+
+	for _, v := range []bool{true, false} {
+		_, errs := r.Validate(newConfig(map[string]interface{}{"name": "net", "default_labels_applied": v}))
+		if len(errs) == 0 {
+			t.Fatalf("expected default_labels_applied = %t to be rejected", v)
+		}
+	}
+	if _, errs := r.Validate(newConfig(map[string]interface{}{"name": "net"})); len(errs) != 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+
+	// Read records a missing default label as false, which the Default
+	// turns into an update.
+	state := &terraform.InstanceState{
+		ID:         "network-uuid",
+		Attributes: map[string]string{"name": "net", "default_labels_applied": "false"},
+	}
+	diff, err := r.Diff(state, newConfig(map[string]interface{}{"name": "net"}))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff == nil || diff.Attributes["default_labels_applied"] == nil || diff.Attributes["default_labels_applied"].New != "true" {
+		t.Fatalf("expected an update of default_labels_applied, got %v", diff)
+	}
+}
+
+func TestParseLabel(t *testing.T) {
+	cases := []struct {
+		label, key, value string
+		ok                bool
+	}{
+		{"env=prod", "env", "prod", true},
+		{"url=a=b", "url", "a=b", true},
+		{"empty=", "empty", "", true},
+		{"plain", "", "", false},
+		{"=value", "", "", false},
+	}
+	for _, tc := range cases {
+		key, value, ok := parseLabel(tc.label)
+		if key != tc.key || value != tc.value || ok != tc.ok {
+			t.Errorf("parseLabel(%q) = %q, %q, %t, expected %q, %q, %t", tc.label, key, value, ok, tc.key, tc.value, tc.ok)
+		}
+	}
+}
+
+func TestCheckDeleteProtection(t *testing.T) {
+	if err := checkDeleteProtection("Storage x", []string{deleteProtectionLabel}); err == nil {
+		t.Fatal("expected a protected object to be refused")
+	}
+	if err := checkDeleteProtection("Storage x", []string{"db"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestFindLocation(t *testing.T) {
+	locations := []gridscale.Location{
+		{ID: "45ed677b-3702-4b36-be2a-a2eab9827950", Name: "de/fra", Iata: "fra"},
+		{ID: "aa5a8b65-b4ae-4b5c-9fb6-b4e6b7e7c5b1", Name: "nl/ams", Iata: "ams"},
+	}
+	cases := []struct {
+		ref      string
+		expected string
+	}{
+		{"45ed677b-3702-4b36-be2a-a2eab9827950", "45ed677b-3702-4b36-be2a-a2eab9827950"},
+		{"AMS", "aa5a8b65-b4ae-4b5c-9fb6-b4e6b7e7c5b1"},
+		{"de/fra", "45ed677b-3702-4b36-be2a-a2eab9827950"},
+	}
+	for _, tc := range cases {
+		id, err := findLocation(locations, tc.ref)
+		if err != nil {
+			t.Fatalf("findLocation(%q) err: %s", tc.ref, err)
+		}
+		if id != tc.expected {
+			t.Errorf("findLocation(%q) = %q, expected %q", tc.ref, id, tc.expected)
+		}
+	}
+
+	if _, err := findLocation(locations, "lon"); err == nil {
+		t.Fatal("expected an error for an unknown location")
+	}
+	locations = append(locations, gridscale.Location{ID: "other", Name: "fra"})
+	if _, err := findLocation(locations, "fra"); err == nil {
+		t.Fatal("expected an error for an ambiguous location")
+	}
+}
+
+func TestDefaultLocationUUID_retry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(500)
+			return
+		}
+		w.Write([]byte(`{"locations": {"45ed677b-3702-4b36-be2a-a2eab9827950": {"object_uuid": "45ed677b-3702-4b36-be2a-a2eab9827950", "name": "de/fra", "iata": "fra"}}}`))
+	}))
+	defer server.Close()
+
+	client, _ := gridscale.NewClient("user-uuid", "token", server.URL)
+	api_client := &Config{Client: client, Endpoint: server.URL, DefaultLocation: "fra"}
+
+	if _, err := api_client.defaultLocationUUID(); err == nil {
+		t.Fatal("expected the failed lookup to return an error")
+	}
+	// The error is not kept, the next call looks the location up again.
+	id, err := api_client.defaultLocationUUID()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if id != "45ed677b-3702-4b36-be2a-a2eab9827950" {
+		t.Fatalf("unexpected location %q", id)
+	}
+	if _, err := api_client.defaultLocationUUID(); err != nil || requests != 2 {
+		t.Fatalf("expected the found location to be kept, got err %v after %d requests", err, requests)
+	}
+}
+
+func TestReadServerPublicIP(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceGridScaleServer().Schema, map[string]interface{}{
+		"name":             "web",
+		"cores":            1,
+		"memory":           1,
+		"public_network":   true,
+		"auto_assign_ipv4": true,
+	})
+	d.Set("ipv4_id", "ip-uuid")
+
+	readServerPublicIP(d, &serverRelations{PublicIPs: []serverIPRelation{
+		{IPID: "ip-uuid", IP: "185.1.2.3", Family: 4},
+	}}, 4)
+	if d.Get("ipv4_address").(string) != "185.1.2.3" || !d.Get("auto_assign_ipv4").(bool) {
+		t.Fatalf("expected the connected address to be read, got %q", d.Get("ipv4_address"))
+	}
+
+	// A disconnected address keeps its ID, so the next apply reconnects it
+	// instead of allocating another one.
+	readServerPublicIP(d, &serverRelations{}, 4)
+	if d.Get("ipv4_id").(string) != "ip-uuid" {
+		t.Fatalf("expected the IP ID to be kept, got %q", d.Get("ipv4_id"))
+	}
+	if d.Get("ipv4_address").(string) != "" || d.Get("auto_assign_ipv4").(bool) {
+		t.Fatal("expected the address and auto_assign_ipv4 to be cleared")
+	}
+}
+
+// testIsoImageChange applies a change of iso_image_id from oldImage to
+// newImage and returns the changing API calls it made.
+func testIsoImageChange(t *testing.T, oldImage, newImage string) []string {
+	defer func(interval time.Duration) { pollInterval = interval }(pollInterval)
+	pollInterval = 0
+
+	calls := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"server": {"object_uuid": "server-uuid", "status": "active"}}`))
+			return
+		}
+		call := r.Method + " " + r.URL.Path
+		body := map[string]string{}
+		if json.NewDecoder(r.Body).Decode(&body) == nil {
+			call += " " + body["object_uuid"]
+		}
+		calls = append(calls, call)
+		w.WriteHeader(202)
+	}))
+	defer server.Close()
+
+	client, _ := gridscale.NewClient("user-uuid", "token", server.URL)
+	api_client := &Config{Client: client, Endpoint: server.URL}
+
+	r := &schema.Resource{
+		Schema: resourceGridScaleServer().Schema,
+		Update: func(d *schema.ResourceData, meta interface{}) error {
+			return updateServerIsoImage(d, meta.(*Config), d.Id())
+		},
+	}
+	state := &terraform.InstanceState{
+		ID: "server-uuid",
+		Attributes: map[string]string{
+			"name":         "web",
+			"cores":        "1",
+			"memory":       "1",
+			"iso_image_id": oldImage,
+		},
+	}
+	raw := map[string]interface{}{"name": "web", "cores": 1, "memory": 1}
+	if newImage != "" {
+		raw["iso_image_id"] = newImage
+	}
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	diff, err := r.Diff(state, terraform.NewResourceConfig(c))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if _, err := r.Apply(state, diff, api_client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return calls
+}
+
+func TestUpdateServerIsoImage(t *testing.T) {
+	cases := []struct {
+		name               string
+		oldImage, newImage string
+		expected           []string
+	}{
+		{"attach", "", "iso-1", []string{
+			"POST /objects/servers/server-uuid/isoimages iso-1",
+		}},
+		{"swap", "iso-1", "iso-2", []string{
+			"DELETE /objects/servers/server-uuid/isoimages/iso-1",
+			"POST /objects/servers/server-uuid/isoimages iso-2",
+		}},
+		{"detach", "iso-1", "", []string{
+			"DELETE /objects/servers/server-uuid/isoimages/iso-1",
+		}},
+		{"unchanged", "iso-1", "iso-1", []string{}},
+	}
+	for _, tc := range cases {
+		if calls := testIsoImageChange(t, tc.oldImage, tc.newImage); !reflect.DeepEqual(calls, tc.expected) {
+			t.Errorf("%s: expected calls %v, got %v", tc.name, tc.expected, calls)
+		}
+	}
+}
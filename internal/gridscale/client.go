@@ -10,12 +10,20 @@ type Client struct {
 	userID    string
 	authToken string
 	endpoint  string
+
+	httpClient *http.Client
 }
 
 // NewClient creates a new gridscale Client. You have to provide the gridscale
 // API user-id UUID, auth token and the API endpoint URL.
 func NewClient(userID, authToken, endpoint string) (*Client, error) {
-	return &Client{userID, authToken, endpoint}, nil
+	return &Client{userID: userID, authToken: authToken, endpoint: endpoint}, nil
+}
+
+// SetHTTPClient makes the Client send its requests with hc, e.g. to use a
+// proxy or custom TLS settings. By default a plain http.Client is used.
+func (c *Client) SetHTTPClient(hc *http.Client) {
+	c.httpClient = hc
 }
 
 func (c *Client) httpCall(method, path string, body []byte) (*http.Response, error) {
@@ -32,7 +40,10 @@ func (c *Client) httpCall(method, path string, body []byte) (*http.Response, err
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	hc := &http.Client{}
+	hc := c.httpClient
+	if hc == nil {
+		hc = &http.Client{}
+	}
 	return hc.Do(req)
 }
 
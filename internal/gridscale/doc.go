@@ -1,11 +1,15 @@
 // Package gridscale is a Go library to consume the Gridscale API (http://www.gridscale.de).
 // The source is hosted on GitHub at https://github.com/parce-iot/gridscale
 //
+// This is a copy of revision ffa5673d4b894a45af2b2d2aec3b2f39c17d2c19 kept
+// in the provider, with Client.SetHTTPClient added so the provider can send
+// the requests through its own proxy and TLS settings.
+//
 // Usage:
 //    package main
 //
 //    import (
-//        "github.com/parce-iot/gridscale"
+//        "github.com/gridscale/terraform-provider-gridscale/internal/gridscale"
 //
 //        "fmt"
 //        "os"